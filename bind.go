@@ -42,6 +42,16 @@ func (m *insertableEntity) getBind() (Bind, error) {
 	return bind, nil
 }
 
+func (m *insertableEntity) validate(bind Bind) error {
+	if m.restore {
+		return nil
+	}
+	if validationError := m.Schema().validate(m.orm, m.id, bind, m.entity); validationError != nil {
+		return validationError
+	}
+	return nil
+}
+
 func (e *editableEntity) getBind() (newBind, oldBind, forcedNew, forcedOld Bind, err error) {
 	newBind = Bind{}
 	oldBind = Bind{}
@@ -51,6 +61,13 @@ func (e *editableEntity) getBind() (newBind, oldBind, forcedNew, forcedOld Bind,
 	return
 }
 
+func (e *editableEntity) validate(newBind Bind) error {
+	if validationError := e.schema.validate(e.orm, e.id, newBind, e.entity); validationError != nil {
+		return validationError
+	}
+	return nil
+}
+
 func (r *removableEntity) getOldBind() (bind Bind, err error) {
 	bind = Bind{}
 	schema := r.Schema()
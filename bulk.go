@@ -0,0 +1,451 @@
+package beeorm
+
+import (
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const bulkChunkSize = 1000
+
+type bulkCacheActions struct {
+	orm       *ormImplementation
+	pipeLines map[string]*RedisPipeLine
+	actions   []func(orm ORM)
+	reserved  map[*entitySchema]int64
+}
+
+func (b *bulkCacheActions) pipeLine(code string) *RedisPipeLine {
+	pipeLine, has := b.pipeLines[code]
+	if !has {
+		pipeLine = newRedisPipeLine(b.orm, code)
+		b.pipeLines[code] = pipeLine
+	}
+	return pipeLine
+}
+
+func (b *bulkCacheActions) apply() {
+	if b.orm.inTransaction {
+		for _, pipeLine := range b.pipeLines {
+			b.orm.transactionPipeLines = append(b.orm.transactionPipeLines, pipeLine)
+		}
+		b.orm.transactionPostActions = append(b.orm.transactionPostActions, b.actions...)
+		return
+	}
+	for _, pipeLine := range b.pipeLines {
+		if pipeLine.commands > 0 {
+			pipeLine.Exec(b.orm)
+		}
+	}
+	for _, action := range b.actions {
+		action(b.orm)
+	}
+}
+
+type BulkOptions struct {
+	ChunkSize int
+}
+
+type bulkOperation struct {
+	schema     *entitySchema
+	where      Where
+	sql        string
+	args       []any
+	chunkSize  int
+	handleRows func(db DBBase, cache *bulkCacheActions, rows []Bind) error
+}
+
+func UpdateWhere[E any](orm ORM, where Where, changes Bind, opts *BulkOptions) (affected uint64, err error) {
+	return updateWhere(orm.(*ormImplementation), getEntitySchema[E](orm), where, changes, opts)
+}
+
+func DeleteWhere[E any](orm ORM, where Where, opts *BulkOptions) (affected uint64, err error) {
+	return deleteWhere(orm.(*ormImplementation), getEntitySchema[E](orm), where, opts)
+}
+
+func updateWhere(orm *ormImplementation, schema *entitySchema, where Where, changes Bind, opts *BulkOptions) (uint64, error) {
+	if len(changes) == 0 {
+		return 0, nil
+	}
+	operation, err := newUpdateWhereOperation(schema, where, changes, opts)
+	if err != nil {
+		return 0, err
+	}
+	return orm.runBulkChunks(operation)
+}
+
+func newUpdateWhereOperation(schema *entitySchema, where Where, changes Bind, opts *BulkOptions) (*bulkOperation, error) {
+	newBind := Bind{}
+	sql := "UPDATE `" + schema.GetTableName() + "` SET "
+	args := make([]any, 0, len(changes))
+	for column, value := range changes {
+		setter, has := schema.fieldBindSetters[column]
+		if !has || column == "ID" || column == schema.versionColumn {
+			return nil, &BindError{Field: column, Message: "invalid field"}
+		}
+		bindValue, err := setter(value)
+		if err != nil {
+			return nil, err
+		}
+		newBind[column] = bindValue
+		if len(args) > 0 {
+			sql += ","
+		}
+		sql += "`" + column + "`=?"
+		args = append(args, bindValue)
+	}
+	if schema.versionColumn != "" {
+		sql += ",`" + schema.versionColumn + "`=`" + schema.versionColumn + "`+1"
+	}
+	operation := newBulkOperation(schema, where, sql, args, opts)
+	operation.handleRows = func(_ DBBase, cache *bulkCacheActions, rows []Bind) error {
+		updateWhereCache(cache, schema, rows, newBind)
+		return nil
+	}
+	return operation, nil
+}
+
+func deleteWhere(orm *ormImplementation, schema *entitySchema, where Where, opts *BulkOptions) (uint64, error) {
+	sql := "DELETE FROM `" + schema.GetTableName() + "`"
+	if schema.softDelete {
+		sql = "UPDATE `" + schema.GetTableName() + "` SET `" + fakeDeleteColumn + "` = `ID`"
+	}
+	actions := orm.engine.registry.referenceActions[schema.t]
+	for _, action := range actions {
+		if action.onDelete != onDeleteSetNull {
+			return 0, fmt.Errorf("%s is referenced by %s field %s with onDelete=%s, use DeleteEntity instead",
+				schema.t.String(), action.schema.t.String(), action.column, action.onDelete)
+		}
+	}
+	operation := newBulkOperation(schema, where, sql, nil, opts)
+	operation.handleRows = func(db DBBase, cache *bulkCacheActions, rows []Bind) error {
+		deleteWhereCache(cache, schema, rows)
+		if len(actions) == 0 {
+			return nil
+		}
+		ids := make([]uint64, len(rows))
+		for i, row := range rows {
+			ids[i] = row["ID"].(uint64)
+		}
+		for _, action := range actions {
+			child, err := newUpdateWhereOperation(action.schema, NewWhere("`"+action.column+"` IN ?", ids), Bind{action.column: nil}, opts)
+			if err != nil {
+				return err
+			}
+			// children are updated in the same transaction as deleted rows when they share a pool
+			childDB := db
+			if action.schema.GetDB().GetConfig().GetCode() != schema.GetDB().GetConfig().GetCode() {
+				childDB = action.schema.GetDB()
+			}
+			lastID := uint64(0)
+			for {
+				childRows, _, err := orm.runBulkChunk(childDB, cache, child, lastID)
+				if err != nil {
+					return err
+				}
+				if len(childRows) < child.chunkSize {
+					break
+				}
+				lastID = childRows[len(childRows)-1]["ID"].(uint64)
+			}
+		}
+		return nil
+	}
+	return orm.runBulkChunks(operation)
+}
+
+func newBulkOperation(schema *entitySchema, where Where, sql string, args []any, opts *BulkOptions) *bulkOperation {
+	chunkSize := bulkChunkSize
+	if opts != nil && opts.ChunkSize > 0 {
+		chunkSize = opts.ChunkSize
+	}
+	return &bulkOperation{schema: schema, where: schema.excludeDeleted(where), sql: sql, args: args, chunkSize: chunkSize}
+}
+
+func (orm *ormImplementation) runBulkChunks(operation *bulkOperation) (uint64, error) {
+	total := uint64(0)
+	lastID := uint64(0)
+	if !orm.inTransaction {
+		defer orm.releaseAsyncBuffer(orm.asyncBufferReserved)
+	}
+	for {
+		var affected uint64
+		var rows []Bind
+		cache := &bulkCacheActions{orm: orm, pipeLines: make(map[string]*RedisPipeLine)}
+		err := orm.runBulk(operation.schema, func(db DBBase) error {
+			var err error
+			rows, affected, err = orm.runBulkChunk(db, cache, operation, lastID)
+			return err
+		})
+		if err != nil {
+			if !orm.inTransaction {
+				orm.releaseAsyncBuffer(cache.reserved)
+			}
+			return total, err
+		}
+		if len(rows) == 0 {
+			return total, nil
+		}
+		total += affected
+		lastID = rows[len(rows)-1]["ID"].(uint64)
+		cache.apply()
+		if !orm.inTransaction {
+			orm.releaseAsyncBuffer(cache.reserved)
+		}
+		if len(rows) < operation.chunkSize {
+			return total, nil
+		}
+	}
+}
+
+func (orm *ormImplementation) runBulkChunk(db DBBase, cache *bulkCacheActions, operation *bulkOperation, lastID uint64) (rows []Bind, affected uint64, err error) {
+	schema := operation.schema
+	rows = loadRowsForUpdate(orm, db, schema, operation.where, lastID, operation.chunkSize)
+	if len(rows) == 0 {
+		return nil, 0, nil
+	}
+	if logTableSchema, hasLogTable := orm.engine.registry.entityLogSchemas[schema.t]; hasLogTable {
+		orm.countAsyncEvents(logTableSchema, int64(len(rows)))
+		reserved, err := orm.reserveAsyncBuffer()
+		if err != nil {
+			return nil, 0, err
+		}
+		if cache.reserved == nil {
+			cache.reserved = make(map[*entitySchema]int64)
+		}
+		for reservedSchema, total := range reserved {
+			cache.reserved[reservedSchema] += total
+		}
+	}
+	ids := make([]any, len(rows))
+	for i, row := range rows {
+		ids[i] = row["ID"]
+	}
+	query := operation.sql + " WHERE `ID` IN (?" + strings.Repeat(",?", len(ids)-1) + ")"
+	affected = db.Exec(orm, query, append(slices.Clone(operation.args), ids...)...).RowsAffected()
+	return rows, affected, operation.handleRows(db, cache, rows)
+}
+
+func updateWhereCache(cache *bulkCacheActions, schema *entitySchema, rows []Bind, newBind Bind) {
+	orm := cache.orm
+	logTableSchema, hasLogTable := orm.engine.registry.entityLogSchemas[schema.t]
+	hasSubscribers := schema.hasSubscribers()
+	for _, oldBind := range rows {
+		id := oldBind["ID"].(uint64)
+		idAsString := strconv.FormatUint(id, 10)
+		if schema.hasLocalCache {
+			cache.actions = append(cache.actions, func(o ORM) {
+				schema.localCache.removeEntity(o, id)
+			})
+		}
+		if schema.hasRedisCache {
+			cache.pipeLine(schema.redisCache.GetCode()).Del(schema.getCacheKey() + ":" + idAsString)
+		}
+		for indexName, definition := range schema.cachedUniqueIndexes {
+			if !slicesContainsAny(mapKeys(newBind), definition.Columns) {
+				continue
+			}
+			hSetKey := schema.getCacheKey() + ":" + indexName
+			hField, hasKey := buildUniqueKeyHSetField(schema, definition.Columns, newBind, oldBind)
+			if hasKey {
+				cache.pipeLine(schema.getForcedRedisCode()).HSet(hSetKey, hField, idAsString)
+			}
+			hFieldOld, hasKey := buildUniqueKeyHSetField(schema, definition.Columns, oldBind, nil)
+			if hasKey {
+				cache.pipeLine(schema.getForcedRedisCode()).HDel(hSetKey, hFieldOld)
+			}
+		}
+		for columnName := range schema.cachedReferences {
+			value, has := newBind[columnName]
+			if !has {
+				continue
+			}
+			newID, _ := value.(uint64)
+			oldID, _ := oldBind[columnName].(uint64)
+			if newID == oldID {
+				continue
+			}
+			if oldID > 0 {
+				cache.removeFromSet(schema, columnName, oldID, idAsString)
+			}
+			if newID > 0 {
+				cache.addToSet(schema, columnName, newID, idAsString)
+			}
+		}
+		for indexName, definition := range schema.cachedIndexes {
+			if !slicesContainsAny(mapKeys(newBind), definition.Columns) {
+				continue
+			}
+			attributes := make([]any, len(definition.Columns))
+			for i, column := range definition.Columns {
+				attributes[i] = oldBind[column]
+			}
+			oldHash := hashIndexAttributes(attributes)
+			for i, column := range definition.Columns {
+				value, has := newBind[column]
+				if has {
+					attributes[i] = value
+				}
+			}
+			newHash := hashIndexAttributes(attributes)
+			if oldHash == newHash {
+				continue
+			}
+			cache.removeFromSet(schema, indexName, oldHash, idAsString)
+			cache.addToSet(schema, indexName, newHash, idAsString)
+		}
+		if !hasLogTable && !hasSubscribers {
+			continue
+		}
+		before := Bind{}
+		after := maps.Clone(newBind)
+		for column := range newBind {
+			before[column] = oldBind[column]
+		}
+		if schema.versionColumn != "" {
+			version, _ := oldBind[schema.versionColumn].(uint64)
+			before[schema.versionColumn] = version
+			after[schema.versionColumn] = version + 1
+		}
+		if hasLogTable {
+			logEvent := orm.newLogTableEvent(schema, logTableSchema, id, before, newBind)
+			cache.actions = append(cache.actions, func(_ ORM) {
+				publishAsyncEvent(orm, logTableSchema, logEvent)
+			})
+		}
+		if hasSubscribers {
+			cache.addFlushedEvent(&flushedEvent{schema: schema, flushType: Update, id: id, before: before, after: after})
+		}
+	}
+}
+
+func deleteWhereCache(cache *bulkCacheActions, schema *entitySchema, rows []Bind) {
+	orm := cache.orm
+	logTableSchema, hasLogTable := orm.engine.registry.entityLogSchemas[schema.t]
+	hasSubscribers := schema.hasSubscribers()
+	for _, oldBind := range rows {
+		id := oldBind["ID"].(uint64)
+		idAsString := strconv.FormatUint(id, 10)
+		for indexName, indexColumns := range schema.GetUniqueIndexes() {
+			hField, hasKey := buildUniqueKeyHSetField(schema, indexColumns, oldBind, nil)
+			if hasKey {
+				cache.pipeLine(schema.getForcedRedisCode()).HDel(schema.getCacheKey()+":"+indexName, hField)
+			}
+		}
+		if schema.hasLocalCache {
+			cache.actions = append(cache.actions, func(o ORM) {
+				schema.localCache.updateEntity(o, id, nil)
+			})
+		}
+		if schema.hasRedisCache {
+			cacheKey := schema.getCacheKey() + ":" + idAsString
+			cache.pipeLine(schema.redisCache.GetCode()).Del(cacheKey)
+			cache.pipeLine(schema.redisCache.GetCode()).LPush(cacheKey, cacheNilValue)
+			schema.setRedisCacheTTL(cache.pipeLine(schema.redisCache.GetCode()), cacheKey)
+		}
+		for columnName := range schema.cachedReferences {
+			refID, _ := oldBind[columnName].(uint64)
+			if refID > 0 {
+				cache.removeFromSet(schema, columnName, refID, idAsString)
+			}
+		}
+		if schema.cacheAll {
+			cache.removeFromSet(schema, cacheAllFakeReferenceKey, 0, idAsString)
+		}
+		for indexName, definition := range schema.cachedIndexes {
+			attributes := make([]any, len(definition.Columns))
+			for i, column := range definition.Columns {
+				attributes[i] = oldBind[column]
+			}
+			cache.removeFromSet(schema, indexName, hashIndexAttributes(attributes), idAsString)
+		}
+		if hasLogTable {
+			logEvent := orm.newLogTableEvent(schema, logTableSchema, id, oldBind, nil)
+			cache.actions = append(cache.actions, func(_ ORM) {
+				publishAsyncEvent(orm, logTableSchema, logEvent)
+			})
+		}
+		if hasSubscribers {
+			cache.addFlushedEvent(&flushedEvent{schema: schema, flushType: Delete, id: id, before: oldBind})
+		}
+	}
+}
+
+func (b *bulkCacheActions) addFlushedEvent(event *flushedEvent) {
+	event.meta = maps.Clone(b.orm.meta)
+	b.actions = append(b.actions, func(o ORM) {
+		b.orm.dispatchFlushedEvents(event)
+	})
+}
+
+func (b *bulkCacheActions) addToSet(schema *entitySchema, key string, id uint64, member string) {
+	if schema.hasLocalCache {
+		b.actions = append(b.actions, func(o ORM) {
+			schema.localCache.removeList(o, key, id)
+		})
+	}
+	redisSetKey := schema.cacheKey + ":" + key + ":" + strconv.FormatUint(id, 10)
+	b.pipeLine(schema.getForcedRedisCode()).SAdd(redisSetKey, member)
+	schema.setRedisCacheTTL(b.pipeLine(schema.getForcedRedisCode()), redisSetKey)
+}
+
+func (b *bulkCacheActions) removeFromSet(schema *entitySchema, key string, id uint64, member string) {
+	if schema.hasLocalCache {
+		b.actions = append(b.actions, func(o ORM) {
+			schema.localCache.removeList(o, key, id)
+		})
+	}
+	redisSetKey := schema.cacheKey + ":" + key
+	if key != cacheAllFakeReferenceKey {
+		redisSetKey += ":" + strconv.FormatUint(id, 10)
+	}
+	b.pipeLine(schema.getForcedRedisCode()).SRem(redisSetKey, member)
+}
+
+func (orm *ormImplementation) runBulk(schema *entitySchema, f func(db DBBase) error) error {
+	db := schema.GetDB()
+	if orm.inTransaction {
+		return f(db)
+	}
+	tx := db.Begin(orm)
+	defer tx.Rollback(orm)
+	err := f(tx)
+	if err != nil {
+		return err
+	}
+	tx.Commit(orm)
+	return nil
+}
+
+func loadRowsForUpdate(orm ORM, db DBBase, schema *entitySchema, where Where, lastID uint64, chunkSize int) []Bind {
+	/* #nosec */
+	query := "SELECT " + schema.fieldsQuery + " FROM `" + schema.GetTableName() + "` WHERE `ID` > ? AND (" + where.String() +
+		") ORDER BY `ID` LIMIT " + strconv.Itoa(chunkSize) + " FOR UPDATE"
+	results, closeRows := db.Query(orm, query, append([]any{lastID}, where.GetParameters()...)...)
+	defer closeRows()
+	var rows []Bind
+	for results.Next() {
+		pointers := prepareScan(schema)
+		results.Scan(pointers...)
+		value := reflect.New(schema.t)
+		deserializeFromDB(schema.fields, value.Elem(), pointers)
+		bind := Bind{}
+		err := fillBindFromOneSource(orm, bind, value.Elem(), schema.fields, "")
+		checkError(err)
+		bind["ID"] = value.Elem().Field(0).Uint()
+		rows = append(rows, bind)
+	}
+	closeRows()
+	return rows
+}
+
+func mapKeys(bind Bind) []string {
+	keys := make([]string, 0, len(bind))
+	for key := range bind {
+		keys = append(keys, key)
+	}
+	return keys
+}
@@ -0,0 +1,146 @@
+package beeorm
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type bulkEntityReference struct {
+	ID uint64
+}
+
+type bulkEntity struct {
+	ID        uint64                         `orm:"localCache;redisCache"`
+	Name      string                         `orm:"required;unique=Name;cached"`
+	Age       uint8                          `orm:"index=Age;cached"`
+	Reference Reference[bulkEntityReference] `orm:"cached"`
+}
+
+func TestBulkNoCache(t *testing.T) {
+	testBulk(t, false, false)
+}
+
+func TestBulkLocalCache(t *testing.T) {
+	testBulk(t, true, false)
+}
+
+func TestBulkRedis(t *testing.T) {
+	testBulk(t, false, true)
+}
+
+func TestBulkLocalCacheRedis(t *testing.T) {
+	testBulk(t, true, true)
+}
+
+func testBulk(t *testing.T, local, redis bool) {
+	var entity *bulkEntity
+	var reference *bulkEntityReference
+	orm := PrepareTables(t, NewRegistry(), entity, reference)
+	schema := GetEntitySchema[bulkEntity](orm)
+	schema.DisableCache(!local, !redis)
+
+	ref1 := NewEntity[bulkEntityReference](orm)
+	ref2 := NewEntity[bulkEntityReference](orm)
+	var ids []uint64
+	for i := 1; i <= 10; i++ {
+		entity = NewEntity[bulkEntity](orm)
+		entity.Name = fmt.Sprintf("name %d", i)
+		entity.Age = uint8(i % 2)
+		entity.Reference = Reference[bulkEntityReference](ref1.ID)
+		ids = append(ids, entity.ID)
+	}
+	assert.NoError(t, orm.Flush())
+
+	var events []FlushedEvent[bulkEntity]
+	Subscribe[bulkEntity](orm.Engine(), func(_ ORM, event FlushedEvent[bulkEntity]) {
+		events = append(events, event)
+	})
+
+	/* warm up caches */
+	GetByIDs[bulkEntity](orm, ids...)
+	assert.Equal(t, 5, GetByIndex[bulkEntity](orm, "Age", 1).Len())
+	assert.Equal(t, 10, GetByReference[bulkEntity](orm, "Reference", ref1.ID).Len())
+
+	affected, err := UpdateWhere[bulkEntity](orm, NewWhere("Age = ?", 1), Bind{"Age": 3, "Reference": ref2.ID}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(5), affected)
+	entity, _ = GetByID[bulkEntity](orm, ids[0])
+	assert.Equal(t, uint8(3), entity.Age)
+	assert.Equal(t, ref2.ID, uint64(entity.Reference))
+	assert.Equal(t, 0, GetByIndex[bulkEntity](orm, "Age", 1).Len())
+	assert.Equal(t, 5, GetByIndex[bulkEntity](orm, "Age", 3).Len())
+	assert.Equal(t, 5, GetByReference[bulkEntity](orm, "Reference", ref1.ID).Len())
+	assert.Equal(t, 5, GetByReference[bulkEntity](orm, "Reference", ref2.ID).Len())
+	assert.Len(t, events, 5)
+	assert.Equal(t, Update, events[0].FlushType())
+	assert.Equal(t, uint64(1), events[0].Before()["Age"])
+	assert.Equal(t, uint64(3), events[0].After()["Age"])
+
+	affected, err = UpdateWhere[bulkEntity](orm, NewWhere("ID = ?", ids[0]), Bind{"Name": "new name"}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1), affected)
+	_, found := GetByUniqueIndex[bulkEntity](orm, "Name", "name 1")
+	assert.False(t, found)
+	entity, found = GetByUniqueIndex[bulkEntity](orm, "Name", "new name")
+	assert.True(t, found)
+	assert.Equal(t, ids[0], entity.ID)
+
+	_, err = UpdateWhere[bulkEntity](orm, NewWhere("1"), Bind{"Invalid": 1}, nil)
+	assert.EqualError(t, err, "[Invalid] invalid field")
+
+	events = events[0:0]
+	affected, err = DeleteWhere[bulkEntity](orm, NewWhere("Age = ?", 3), nil)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(5), affected)
+	assert.Len(t, events, 5)
+	assert.Equal(t, Delete, events[0].FlushType())
+	_, found = GetByID[bulkEntity](orm, ids[0])
+	assert.False(t, found)
+	_, found = GetByUniqueIndex[bulkEntity](orm, "Name", "new name")
+	assert.False(t, found)
+	assert.Equal(t, 0, GetByIndex[bulkEntity](orm, "Age", 3).Len())
+	assert.Equal(t, 0, GetByReference[bulkEntity](orm, "Reference", ref2.ID).Len())
+	assert.Equal(t, 5, GetByReference[bulkEntity](orm, "Reference", ref1.ID).Len())
+	affected, err = DeleteWhere[bulkEntity](orm, NewWhere("Age = ?", 3), nil)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(0), affected)
+}
+
+type bulkSetNullParent struct {
+	ID uint64
+}
+
+type bulkSetNullChild struct {
+	ID     uint64
+	Parent Reference[bulkSetNullParent] `orm:"onDelete=setNull"`
+}
+
+func TestBulkDeleteSetNull(t *testing.T) {
+	var parent *bulkSetNullParent
+	var child *bulkSetNullChild
+	orm := PrepareTables(t, NewRegistry(), parent, child)
+	var childIDs []uint64
+	for i := 0; i < 3; i++ {
+		parent = NewEntity[bulkSetNullParent](orm)
+		child = NewEntity[bulkSetNullChild](orm)
+		child.Parent = Reference[bulkSetNullParent](parent.ID)
+		childIDs = append(childIDs, child.ID)
+	}
+	assert.NoError(t, orm.Flush())
+
+	loggerDB := &MockLogHandler{}
+	orm.RegisterQueryLogger(loggerDB, true, false, false)
+	affected, err := DeleteWhere[bulkSetNullParent](orm, NewWhere("1"), &BulkOptions{ChunkSize: 2})
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(3), affected)
+	assert.Len(t, loggerDB.Logs, 13)
+	assert.Equal(t, "START TRANSACTION", loggerDB.Logs[0]["query"])
+	assert.Contains(t, loggerDB.Logs[2]["query"], "DELETE FROM `bulkSetNullParent`")
+	assert.Contains(t, loggerDB.Logs[4]["query"], "UPDATE `bulkSetNullChild`")
+	assert.Equal(t, "COMMIT", loggerDB.Logs[6]["query"])
+	for _, child = range GetByIDs[bulkSetNullChild](orm, childIDs...).All() {
+		assert.Equal(t, uint64(0), child.Parent.GetID())
+	}
+}
@@ -4,10 +4,15 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"regexp"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 )
 
+var savepointNameRegexp = regexp.MustCompile("^[A-Za-z0-9_]+$")
+
 type MySQLConfig interface {
 	GetCode() string
 	GetDatabaseName() string
@@ -17,11 +22,13 @@ type MySQLConfig interface {
 }
 
 type mySQLConfig struct {
-	dataSourceName string
-	code           string
-	databaseName   string
-	client         *sql.DB
-	options        *MySQLOptions
+	dataSourceName       string
+	code                 string
+	databaseName         string
+	client               *sql.DB
+	options              *MySQLOptions
+	maxAllowedPacket     int
+	maxAllowedPacketLock sync.Mutex
 }
 
 func (p *mySQLConfig) GetCode() string {
@@ -319,14 +326,20 @@ type DB interface {
 
 type DBTransaction interface {
 	DBBase
+	Begin(orm ORM) DBTransaction
 	Commit(orm ORM)
 	Rollback(orm ORM)
+	Savepoint(orm ORM, name string)
+	RollbackTo(orm ORM, name string)
+	Release(orm ORM, name string)
 }
 
 type dbImplementation struct {
 	client      sqlClient
 	config      MySQLConfig
 	transaction bool
+	savepoint   string
+	savepoints  *int
 }
 
 func (db *dbImplementation) GetConfig() MySQLConfig {
@@ -337,6 +350,11 @@ func (db *dbImplementation) Commit(orm ORM) {
 	if !db.transaction {
 		return
 	}
+	if db.savepoint != "" {
+		db.Release(orm, db.savepoint)
+		db.transaction = false
+		return
+	}
 	hasLogger, _ := orm.getDBLoggers()
 	start := getNow(hasLogger)
 	err := db.client.(txClient).Commit()
@@ -351,6 +369,11 @@ func (db *dbImplementation) Rollback(orm ORM) {
 	if !db.transaction {
 		return
 	}
+	if db.savepoint != "" {
+		db.RollbackTo(orm, db.savepoint)
+		db.transaction = false
+		return
+	}
 	hasLogger, _ := orm.getDBLoggers()
 	start := getNow(hasLogger)
 	err := db.client.(txClient).Rollback()
@@ -362,6 +385,17 @@ func (db *dbImplementation) Rollback(orm ORM) {
 }
 
 func (db *dbImplementation) Begin(orm ORM) DBTransaction {
+	if db.transaction {
+		return db.beginSavepoint(orm)
+	}
+	ormImpl, isImpl := orm.(*ormImplementation)
+	if isImpl && ormImpl.inTransaction {
+		return ormImpl.getDBTransaction(db).beginSavepoint(orm)
+	}
+	return db.begin(orm)
+}
+
+func (db *dbImplementation) begin(orm ORM) *dbImplementation {
 	hasLogger, _ := orm.getDBLoggers()
 	start := getNow(hasLogger)
 	tx, err := db.client.Begin()
@@ -369,10 +403,73 @@ func (db *dbImplementation) Begin(orm ORM) DBTransaction {
 		db.fillLogFields(orm, "TRANSACTION", "START TRANSACTION", start, err)
 	}
 	checkError(err)
-	dbTX := &dbImplementation{config: db.config, client: &txSQLClient{standardSQLClient{db: tx}, tx}, transaction: true}
+	dbTX := &dbImplementation{config: db.config, client: &txSQLClient{standardSQLClient{db: tx}, tx}, transaction: true, savepoints: new(int)}
 	return dbTX
 }
 
+func (db *dbImplementation) beginSavepoint(orm ORM) *dbImplementation {
+	*db.savepoints++
+	name := "beeorm_savepoint_" + strconv.Itoa(*db.savepoints)
+	db.Savepoint(orm, name)
+	return &dbImplementation{config: db.config, client: db.client, transaction: true, savepoint: name, savepoints: db.savepoints}
+}
+
+func (db *dbImplementation) Savepoint(orm ORM, name string) {
+	db.execTransactionStatement(orm, "SAVEPOINT "+quoteSavepointName(name))
+}
+
+func (db *dbImplementation) RollbackTo(orm ORM, name string) {
+	db.execTransactionStatement(orm, "ROLLBACK TO SAVEPOINT "+quoteSavepointName(name))
+}
+
+func (db *dbImplementation) Release(orm ORM, name string) {
+	db.execTransactionStatement(orm, "RELEASE SAVEPOINT "+quoteSavepointName(name))
+}
+
+func quoteSavepointName(name string) string {
+	if !savepointNameRegexp.MatchString(name) {
+		panic(fmt.Errorf("invalid savepoint name '%s'", name))
+	}
+	return "`" + name + "`"
+}
+
+func (db *dbImplementation) execTransactionStatement(orm ORM, query string) {
+	if !db.transaction {
+		panic(fmt.Errorf("%s requires transaction", query))
+	}
+	hasLogger, _ := orm.getDBLoggers()
+	start := getNow(hasLogger)
+	_, err := db.client.Exec(query)
+	if hasLogger {
+		db.fillLogFields(orm, "TRANSACTION", query, start, err)
+	}
+	checkError(err)
+}
+
+func (db *dbImplementation) inTransaction(orm ORM) *dbImplementation {
+	if db.transaction {
+		return db
+	}
+	ormImpl, isImpl := orm.(*ormImplementation)
+	if !isImpl || !ormImpl.inTransaction {
+		return db
+	}
+	return ormImpl.getDBTransaction(db)
+}
+
+func (db *dbImplementation) getMaxAllowedPacket() int {
+	config := db.config.(*mySQLConfig)
+	config.maxAllowedPacketLock.Lock()
+	defer config.maxAllowedPacketLock.Unlock()
+	if config.maxAllowedPacket == 0 {
+		var size int
+		if db.client.QueryRow("SELECT @@max_allowed_packet").Scan(&size) == nil {
+			config.maxAllowedPacket = size
+		}
+	}
+	return config.maxAllowedPacket
+}
+
 func (db *dbImplementation) GetDBClient() DBClient {
 	return db.client.(*standardSQLClient).db
 }
@@ -382,6 +479,7 @@ func (db *dbImplementation) SetMockDBClient(mock DBClient) {
 }
 
 func (db *dbImplementation) Prepare(orm ORM, query string) (stmt PreparedStmt, close func()) {
+	db = db.inTransaction(orm)
 	hasLogger, _ := orm.getDBLoggers()
 	start := getNow(hasLogger)
 	result, err := db.client.Prepare(query)
@@ -404,6 +502,7 @@ func (db *dbImplementation) Exec(orm ORM, query string, args ...any) ExecResult
 }
 
 func (db *dbImplementation) exec(orm ORM, query string, args ...any) (ExecResult, error) {
+	db = db.inTransaction(orm)
 	hasLogger, _ := orm.getDBLoggers()
 	start := getNow(hasLogger)
 	rows, err := db.client.Exec(query, args...)
@@ -418,6 +517,7 @@ func (db *dbImplementation) exec(orm ORM, query string, args ...any) (ExecResult
 }
 
 func (db *dbImplementation) QueryRow(orm ORM, query Where, toFill ...any) (found bool) {
+	db = db.inTransaction(orm)
 	hasLogger, _ := orm.getDBLoggers()
 	start := getNow(hasLogger)
 	row := db.client.QueryRow(query.String(), query.GetParameters()...)
@@ -448,6 +548,7 @@ func (db *dbImplementation) QueryRow(orm ORM, query Where, toFill ...any) (found
 }
 
 func (db *dbImplementation) Query(orm ORM, query string, args ...any) (rows Rows, close func()) {
+	db = db.inTransaction(orm)
 	hasLogger, _ := orm.getDBLoggers()
 	start := getNow(hasLogger)
 	result, err := db.client.Query(query, args...)
@@ -1,8 +1,12 @@
 package beeorm
 
 import (
+	"database/sql"
+	"fmt"
 	"hash/maphash"
+	"math"
 	"reflect"
+	"strconv"
 
 	"github.com/puzpuzpuz/xsync/v2"
 )
@@ -12,6 +16,13 @@ func EditEntityField(orm ORM, entity any, field string, value any) error {
 }
 
 func editEntityField(orm ORM, entity any, field string, value any) error {
+	cImplementation := orm.(*ormImplementation)
+	cImplementation.mutexFlush.Lock()
+	defer cImplementation.mutexFlush.Unlock()
+	return cImplementation.trackEntityField(entity, field, value)
+}
+
+func (orm *ormImplementation) trackEntityField(entity any, field string, value any) error {
 	schema := getEntitySchemaFromSource(orm, entity)
 	setter, has := schema.fieldBindSetters[field]
 	if !has {
@@ -30,6 +41,104 @@ func editEntityField(orm ORM, entity any, field string, value any) error {
 		return nil
 	}
 	id := elem.Field(0).Uint()
+	if orm.trackedEntities == nil {
+		orm.trackedEntities = xsync.NewTypedMapOf[uint64, *xsync.MapOf[uint64, EntityFlush]](func(seed maphash.Seed, u uint64) uint64 {
+			return u
+		})
+	}
+	entities, _ := orm.trackedEntities.LoadOrCompute(schema.index, func() *xsync.MapOf[uint64, EntityFlush] {
+		return xsync.NewTypedMapOf[uint64, EntityFlush](func(seed maphash.Seed, u uint64) uint64 {
+			return u
+		})
+	})
+	actual, loaded := entities.LoadOrCompute(id, func() EntityFlush {
+		editable := &editableFields{}
+		editable.orm = orm
+		editable.schema = schema
+		editable.id = id
+		editable.value = reflectValue
+		editable.newBind = Bind{field: newValue}
+		editable.oldBind = Bind{field: oldValue}
+		addUniqueIndexFieldsToBind(schema, field, editable.oldBind, editable.newBind, elem)
+		return editable
+	})
+	if !loaded {
+		return nil
+	}
+	editable, is := actual.(*editableFields)
+	if is {
+		editable.newBind[field] = newValue
+		editable.oldBind[field] = oldValue
+		delete(editable.increments, field)
+		addUniqueIndexFieldsToBind(schema, field, editable.oldBind, editable.newBind, elem)
+		return nil
+	}
+	fSetter := schema.fieldSetters[field]
+	editableE, is := actual.(*editableEntity)
+	if is {
+		fSetter(newValue, editableE.value.Elem())
+		return nil
+	}
+	insertableE, is := actual.(*insertableEntity)
+	if is {
+		fSetter(newValue, insertableE.value.Elem())
+		return nil
+	}
+	return &BindError{Field: field, Message: "setting field in entity marked to delete not allowed"}
+}
+
+func addUniqueIndexFieldsToBind(schema *entitySchema, field string, oldBind, newBind Bind, elem reflect.Value) {
+	uniqueIndexes := schema.GetUniqueIndexes()
+	for _, indexColumns := range uniqueIndexes {
+		for _, indexColumn := range indexColumns {
+			if indexColumn == field {
+				for _, indexColumnToAdd := range indexColumns {
+					if oldBind[indexColumnToAdd] == nil {
+						setter, _ := schema.fieldBindSetters[indexColumnToAdd]
+						val, _ := setter(elem.FieldByName(indexColumnToAdd).Interface())
+						newBind[indexColumnToAdd] = val
+						oldBind[indexColumnToAdd] = val
+					}
+				}
+				break
+			}
+		}
+	}
+}
+
+func IncrementEntityField(orm ORM, entity any, field string, delta any) error {
+	schema := getEntitySchemaFromSource(orm, entity)
+	setter, has := schema.fieldBindSetters[field]
+	if !has {
+		return &BindError{field, "unknown field"}
+	}
+	_, isReference := schema.references[field]
+	if field == "ID" || isReference {
+		return &BindError{field, "field can't be incremented"}
+	}
+	reflectValue := reflect.ValueOf(entity)
+	elem := reflectValue.Elem()
+	getter := schema.fieldGetters[field]
+	v := reflect.ValueOf(getter(elem))
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return &BindError{field, "nil value can't be incremented"}
+		}
+		v = v.Elem()
+	}
+	newValue, sqlDelta, err := incrementFieldValue(field, v, delta)
+	if err != nil {
+		return err
+	}
+	if reflect.ValueOf(sqlDelta).IsZero() {
+		return nil
+	}
+	newBindValue, err := setter(newValue)
+	if err != nil {
+		return err
+	}
+	oldValue, _ := setter(getter(elem))
+	id := elem.Field(0).Uint()
 	cImplementation := orm.(*ormImplementation)
 	var asyncError error
 	func() {
@@ -51,51 +160,154 @@ func editEntityField(orm ORM, entity any, field string, value any) error {
 			editable.schema = schema
 			editable.id = id
 			editable.value = reflectValue
-			editable.newBind = Bind{field: newValue}
+			editable.newBind = Bind{field: newBindValue}
 			editable.oldBind = Bind{field: oldValue}
+			editable.increments = Bind{field: sqlDelta}
 			addUniqueIndexFieldsToBind(schema, field, editable.oldBind, editable.newBind, elem)
 			return editable
 		})
 		if loaded {
 			editable, is := actual.(*editableFields)
 			if is {
-				editable.newBind[field] = newValue
+				_, isEdited := editable.newBind[field]
+				before, isIncremented := editable.increments[field]
+				if isEdited && !isIncremented {
+					asyncError = &BindError{Field: field, Message: "incrementing field with pending value not allowed"}
+					return
+				}
+				if isIncremented {
+					switch before.(type) {
+					case int64:
+						sqlDelta = before.(int64) + sqlDelta.(int64)
+					case float64:
+						sqlDelta = before.(float64) + sqlDelta.(float64)
+					}
+					newValue, _, asyncError = incrementFieldValue(field, v, sqlDelta)
+					if asyncError != nil {
+						return
+					}
+					newBindValue, asyncError = setter(newValue)
+					if asyncError != nil {
+						return
+					}
+				}
+				if editable.increments == nil {
+					editable.increments = Bind{}
+				}
+				editable.increments[field] = sqlDelta
+				editable.newBind[field] = newBindValue
 				editable.oldBind[field] = oldValue
 				addUniqueIndexFieldsToBind(schema, field, editable.oldBind, editable.newBind, elem)
 				return
 			}
-			fSetter := schema.fieldSetters[field]
-			editableE, is := actual.(*editableEntity)
-			if is {
-				fSetter(newValue, editableE.value.Elem())
-				return
+			// the whole row is written for these entities so the increment could not stay atomic
+			switch actual.(type) {
+			case *editableEntity:
+				asyncError = &BindError{Field: field, Message: "incrementing field in edited entity not allowed"}
+			case *insertableEntity:
+				asyncError = &BindError{Field: field, Message: "incrementing field in entity marked to insert not allowed"}
+			default:
+				asyncError = &BindError{Field: field, Message: "incrementing field in entity marked to delete not allowed"}
 			}
-			insertableE, is := actual.(*insertableEntity)
-			if is {
-				fSetter(newValue, insertableE.value.Elem())
-				return
-			}
-			asyncError = &BindError{Field: field, Message: "setting field in entity marked to delete not allowed"}
 		}
 	}()
 	return asyncError
 }
 
-func addUniqueIndexFieldsToBind(schema *entitySchema, field string, oldBind, newBind Bind, elem reflect.Value) {
-	uniqueIndexes := schema.GetUniqueIndexes()
-	for _, indexColumns := range uniqueIndexes {
-		for _, indexColumn := range indexColumns {
-			if indexColumn == field {
-				for _, indexColumnToAdd := range indexColumns {
-					if oldBind[indexColumnToAdd] == nil {
-						setter, _ := schema.fieldBindSetters[indexColumnToAdd]
-						val, _ := setter(elem.FieldByName(indexColumnToAdd).Interface())
-						newBind[indexColumnToAdd] = val
-						oldBind[indexColumnToAdd] = val
-					}
-				}
-				break
+func incrementFieldValue(field string, v reflect.Value, delta any) (newValue, sqlDelta any, err error) {
+	d := reflect.ValueOf(delta)
+	switch v.Kind() {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		var asInt64 int64
+		switch d.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			asInt64 = d.Int()
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			if d.Uint() > math.MaxInt64 {
+				return nil, nil, &BindError{field, fmt.Sprintf("delta %d too big", d.Uint())}
+			}
+			asInt64 = int64(d.Uint())
+		default:
+			return nil, nil, &BindError{field, fmt.Sprintf("invalid delta %v", delta)}
+		}
+		if v.CanUint() {
+			if asInt64 < 0 && uint64(-asInt64) > v.Uint() {
+				return nil, nil, &BindError{field, "negative value not allowed"}
 			}
+			return v.Uint() + uint64(asInt64), asInt64, nil
+		}
+		return v.Int() + asInt64, asInt64, nil
+	case reflect.Float32, reflect.Float64:
+		var asFloat64 float64
+		switch d.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			asFloat64 = float64(d.Int())
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			asFloat64 = float64(d.Uint())
+		case reflect.Float32, reflect.Float64:
+			asFloat64 = d.Float()
+		default:
+			return nil, nil, &BindError{field, fmt.Sprintf("invalid delta %v", delta)}
+		}
+		return v.Float() + asFloat64, asFloat64, nil
+	}
+	return nil, nil, &BindError{field, "only number fields can be incremented"}
+}
+
+func (orm *ormImplementation) appendIncrementsFetchDBAction(schema *entitySchema, update entityFlushUpdate, increments Bind, fetched *Bind) {
+	columns := make([]string, 0, len(increments))
+	for column := range increments {
+		columns = append(columns, column)
+	}
+	id := update.ID()
+	orm.appendDBAction(schema, func(db DBBase) {
+		values, found := fetchEntityColumns(orm, db, schema, id, columns)
+		if !found {
+			return
+		}
+		*fetched = values
+		if !schema.hasRedisCache {
+			return
+		}
+		orm.appendDBCommitAction(func() {
+			p := orm.RedisPipeLine(schema.redisCache.GetCode())
+			rKey := schema.getCacheKey() + ":" + strconv.FormatUint(id, 10)
+			if schema.redisCacheTTL > 0 {
+				schema.updateRedisCacheEntity(p, rKey, values)
+				return
+			}
+			for column, value := range values {
+				p.LSet(rKey, int64(schema.columnMapping[column]+1), convertBindValueToRedisValue(value))
+			}
+		})
+	})
+}
+
+func fetchEntityColumns(orm ORM, db DBBase, schema *entitySchema, id uint64, columns []string) (Bind, bool) {
+	query := "SELECT "
+	for i, column := range columns {
+		if i > 0 {
+			query += ","
+		}
+		query += "`" + column + "`"
+	}
+	query += " FROM `" + schema.GetTableName() + "` WHERE ID = ?"
+	values := make([]sql.NullString, len(columns))
+	pointers := make([]any, len(columns))
+	for i := range values {
+		pointers[i] = &values[i]
+	}
+	if !db.QueryRow(orm, NewWhere(query, id), pointers...) {
+		return nil, false
+	}
+	bind := make(Bind, len(columns))
+	for i, column := range columns {
+		var value any
+		if values[i].Valid {
+			value, _ = schema.fieldBindSetters[column](values[i].String)
 		}
+		bind[column] = value
 	}
+	return bind, true
 }
@@ -39,7 +39,7 @@ func BenchmarkEditByFieldAsync(b *testing.B) {
 		_ = EditEntityField(orm, entity, field, n)
 		_ = orm.FlushAsync()
 	}
-	publishAsyncEvent(schema, nil)
+	publishAsyncEvent(orm, schema, nil)
 }
 
 // BenchmarkEditByFieldAsyncWithRedis-10    	  908324	      1304 ns/op	     688 B/op	      13 allocs/op
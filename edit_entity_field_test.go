@@ -1,8 +1,10 @@
 package beeorm
 
 import (
+	"context"
 	"fmt"
 	"math"
+	"strings"
 	"testing"
 	"time"
 
@@ -515,3 +517,147 @@ func runEditEntityField(orm ORM, entity *updateEntity, field string, value any,
 	}
 	return orm.Flush()
 }
+
+type incrementEntity struct {
+	ID      uint64 `orm:"localCache;redisCache"`
+	Counter uint32
+	Balance int64
+	Price   float64 `orm:"decimal=8,2"`
+	Name    string
+}
+
+func TestIncrementFieldNoCache(t *testing.T) {
+	testIncrementField(t, false, false, false)
+}
+
+func TestIncrementFieldLocalCache(t *testing.T) {
+	testIncrementField(t, false, true, false)
+}
+
+func TestIncrementFieldRedis(t *testing.T) {
+	testIncrementField(t, false, false, true)
+}
+
+func TestIncrementFieldLocalCacheRedis(t *testing.T) {
+	testIncrementField(t, false, true, true)
+}
+
+func TestIncrementFieldNoCacheAsync(t *testing.T) {
+	testIncrementField(t, true, false, false)
+}
+
+func TestIncrementFieldLocalCacheRedisAsync(t *testing.T) {
+	testIncrementField(t, true, true, true)
+}
+
+func testIncrementField(t *testing.T, async, local, redis bool) {
+	var entity *incrementEntity
+	orm := PrepareTables(t, NewRegistry(), entity)
+	schema := GetEntitySchema[incrementEntity](orm)
+	schema.DisableCache(!local, !redis)
+
+	entity = NewEntity[incrementEntity](orm)
+	entity.Counter = 10
+	entity.Balance = 5
+	entity.Price = 1.5
+	assert.NoError(t, orm.Flush())
+
+	flush := func() {
+		if async {
+			assert.NoError(t, orm.FlushAsync())
+			assert.NoError(t, runAsyncConsumer(orm, false))
+			return
+		}
+		assert.NoError(t, orm.Flush())
+	}
+
+	entity, _ = GetByID[incrementEntity](orm, entity.ID)
+	assert.NoError(t, IncrementEntityField(orm, entity, "Counter", 2))
+	assert.NoError(t, IncrementEntityField(orm, entity, "Counter", 3))
+	assert.NoError(t, IncrementEntityField(orm, entity, "Balance", -7))
+	assert.NoError(t, IncrementEntityField(orm, entity, "Price", 0.25))
+	flush()
+	entity, _ = GetByID[incrementEntity](orm, entity.ID)
+	assert.Equal(t, uint32(15), entity.Counter)
+	assert.Equal(t, int64(-2), entity.Balance)
+	assert.Equal(t, 1.75, entity.Price)
+	var counter uint32
+	orm.Engine().DB(DefaultPoolCode).QueryRow(orm, NewWhere("SELECT `Counter` FROM `incrementEntity` WHERE ID = ?", entity.ID), &counter)
+	assert.Equal(t, uint32(15), counter)
+
+	loggerDB := &MockLogHandler{}
+	orm.RegisterQueryLogger(loggerDB, true, false, false)
+	assert.NoError(t, IncrementEntityField(orm, entity, "Counter", 1))
+	flush()
+	hasIncrementQuery := false
+	for _, log := range loggerDB.Logs {
+		if strings.HasPrefix(log["query"].(string), "UPDATE `incrementEntity` SET `Counter`=`Counter`+? WHERE ID = ?") {
+			hasIncrementQuery = true
+		}
+	}
+	assert.True(t, hasIncrementQuery)
+	orm.RegisterQueryLogger(loggerDB, false, false, false)
+
+	if !async {
+		orm.Engine().DB(DefaultPoolCode).Exec(orm, "UPDATE `incrementEntity` SET `Counter` = `Counter` + 100 WHERE ID = ?", entity.ID)
+		assert.NoError(t, IncrementEntityField(orm, entity, "Counter", 1))
+		flush()
+		entity, _ = GetByID[incrementEntity](orm, entity.ID)
+		assert.Equal(t, uint32(117), entity.Counter)
+	}
+
+	assert.EqualError(t, IncrementEntityField(orm, entity, "Name", 1), "[Name] only number fields can be incremented")
+	assert.EqualError(t, IncrementEntityField(orm, entity, "Counter", "a"), "[Counter] invalid delta a")
+	assert.EqualError(t, IncrementEntityField(orm, entity, "Counter", -1000), "[Counter] negative value not allowed")
+
+	edited := EditEntity(orm, entity)
+	assert.EqualError(t, IncrementEntityField(orm, edited, "Counter", 1), "[Counter] incrementing field in edited entity not allowed")
+	orm.ClearFlush()
+	inserted := NewEntity[incrementEntity](orm)
+	assert.EqualError(t, IncrementEntityField(orm, inserted, "Counter", 1), "[Counter] incrementing field in entity marked to insert not allowed")
+	orm.ClearFlush()
+}
+
+func TestIncrementFieldConcurrentAsync(t *testing.T) {
+	var entity *incrementEntity
+	orm := PrepareTables(t, NewRegistry(), entity)
+
+	registry := NewRegistry()
+	registry.RegisterMySQL("root:root@tcp(localhost:3377)/test", DefaultPoolCode, &MySQLOptions{})
+	registry.RegisterRedis("localhost:6385", 0, DefaultPoolCode, nil)
+	registry.RegisterLocalCache(DefaultPoolCode, 0)
+	registry.RegisterEntity(entity)
+	engine, err := registry.Validate()
+	assert.NoError(t, err)
+	orm2 := engine.NewORM(context.Background())
+
+	entity = NewEntity[incrementEntity](orm)
+	entity.Counter = 10
+	assert.NoError(t, orm.Flush())
+
+	entity, _ = GetByID[incrementEntity](orm, entity.ID)
+	entity2, _ := GetByID[incrementEntity](orm2, entity.ID)
+	assert.NoError(t, IncrementEntityField(orm, entity, "Counter", 1))
+	assert.NoError(t, IncrementEntityField(orm2, entity2, "Counter", 1))
+	assert.NoError(t, orm.FlushAsync())
+	assert.NoError(t, orm2.FlushAsync())
+	stop := ConsumeAsyncBuffer(orm2, func(err error) {
+		panic(err)
+	})
+	stop()
+	assert.NoError(t, runAsyncConsumer(orm, false))
+
+	var counter uint32
+	orm.Engine().DB(DefaultPoolCode).QueryRow(orm, NewWhere("SELECT `Counter` FROM `incrementEntity` WHERE ID = ?", entity.ID), &counter)
+	assert.Equal(t, uint32(12), counter)
+	entity, _ = GetByID[incrementEntity](orm, entity.ID)
+	assert.Equal(t, uint32(12), entity.Counter)
+
+	lc2, _ := GetEntitySchema[incrementEntity](orm2).GetLocalCache()
+	lc2.Clear(orm2)
+	loggerDB := &MockLogHandler{}
+	orm2.RegisterQueryLogger(loggerDB, true, false, false)
+	entity2, _ = GetByID[incrementEntity](orm2, entity.ID)
+	assert.Equal(t, uint32(12), entity2.Counter)
+	assert.Len(t, loggerDB.Logs, 0)
+}
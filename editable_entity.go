@@ -21,6 +21,7 @@ type EntityFlush interface {
 type entityFlushInsert interface {
 	EntityFlush
 	getBind() (Bind, error)
+	validate(bind Bind) error
 	getEntity() any
 	getValue() reflect.Value
 }
@@ -34,6 +35,7 @@ type entityFlushDelete interface {
 type entityFlushUpdate interface {
 	EntityFlush
 	getBind() (new, old, newForced, oldForced Bind, err error)
+	validate(newBind Bind) error
 	getValue() reflect.Value
 	getSourceValue() reflect.Value
 	getEntity() any
@@ -41,6 +43,11 @@ type entityFlushUpdate interface {
 
 type EntityFlushedEvent interface {
 	FlushType() FlushType
+	ID() uint64
+	Schema() EntitySchema
+	Before() Bind
+	After() Bind
+	Meta() Meta
 }
 
 type writableEntity struct {
@@ -54,9 +61,11 @@ func (w *writableEntity) Schema() *entitySchema {
 
 type insertableEntity struct {
 	writableEntity
-	entity any
-	id     uint64
-	value  reflect.Value
+	entity  any
+	id      uint64
+	value   reflect.Value
+	upsert  *upsertData
+	restore bool
 }
 
 func (m *insertableEntity) ID() uint64 {
@@ -96,6 +105,7 @@ type removableEntity struct {
 	id     uint64
 	value  reflect.Value
 	source any
+	force  bool
 }
 
 func (r *removableEntity) flushType() FlushType {
@@ -121,10 +131,11 @@ type editableEntity struct {
 
 type editableFields struct {
 	writableEntity
-	id      uint64
-	value   reflect.Value
-	newBind Bind
-	oldBind Bind
+	id         uint64
+	value      reflect.Value
+	newBind    Bind
+	oldBind    Bind
+	increments Bind
 }
 
 func (f *editableFields) ID() uint64 {
@@ -204,6 +215,13 @@ func (f *editableFields) getBind() (new, old, forcedNew, forcedOld Bind, err err
 	return f.newBind, f.oldBind, forcedNew, forcedOld, nil
 }
 
+func (f *editableFields) validate(_ Bind) error {
+	if validationError := f.schema.validateFields(f.orm, f); validationError != nil {
+		return validationError
+	}
+	return nil
+}
+
 func (f *editableFields) getEntity() any {
 	return nil
 }
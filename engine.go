@@ -4,6 +4,7 @@ import (
 	"context"
 	"reflect"
 	"strings"
+	"sync/atomic"
 	"time"
 )
 
@@ -43,6 +44,7 @@ type engineRegistryImplementation struct {
 	entityLogSchemas       map[reflect.Type]*entitySchema
 	defaultQueryLogger     *defaultLogLogger
 	dbTables               map[string]map[string]bool
+	referenceActions       map[reflect.Type][]referenceAction
 	options                map[string]any
 	enums                  map[string][]string
 	asyncConsumerBlockTime time.Duration
@@ -55,7 +57,12 @@ type engineImplementation struct {
 	redisServers                 map[string]RedisCache
 	options                      map[string]any
 	pluginFlush                  []PluginInterfaceEntityFlush
-	asyncTemporaryIsQueueRunning bool
+	flushRetryPolicy             *FlushRetryPolicy
+	asyncFlushStreams            *AsyncFlushStreamsOptions
+	asyncErrorPolicy             *AsyncErrorPolicy
+	asyncBuffer                  *AsyncBufferOptions
+	localCacheInvalidation       *localCacheInvalidationBus
+	asyncTemporaryIsQueueRunning atomic.Bool
 }
 
 func (e *engineImplementation) NewORM(context context.Context) ORM {
@@ -9,6 +9,7 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/pkg/errors"
@@ -61,6 +62,7 @@ type EntitySchema interface {
 	UpdateSchemaAndTruncateTable(orm ORM)
 	GetSchemaChanges(orm ORM) (alters []Alter, has bool)
 	DisableCache(local, redis bool)
+	SetAsyncErrorPolicy(policy *AsyncErrorPolicy)
 	NewEntity(orm ORM) any
 	GetByID(orm ORM, id uint64) (entity any, found bool)
 	Search(orm ORM, where Where, pager *Pager) EntityAnonymousIterator
@@ -111,6 +113,9 @@ type entitySchema struct {
 	hasLocalCache             bool
 	localCache                *localCache
 	localCacheLimit           int
+	localCacheTTL             time.Duration
+	redisCacheTTL             time.Duration
+	redisCacheTTLSliding      bool
 	redisCacheName            string
 	hasRedisCache             bool
 	redisCache                *redisCache
@@ -118,10 +123,20 @@ type entitySchema struct {
 	uuidCacheKey              string
 	uuidMutex                 sync.Mutex
 	asyncCacheKey             string
+	asyncErrorPolicy          atomic.Pointer[AsyncErrorPolicy]
 	structureHash             string
+	versionColumn             string
+	softDelete                bool
+	validators                map[string][]bindValidator
+	hasValidateMethod         bool
+	subscribers               []flushedEventHandler
+	subscribersMutex          sync.RWMutex
 	mapBindToScanPointer      mapBindToScanPointer
 	mapPointerToValue         mapPointerToValue
 	asyncTemporaryQueue       *xsync.MPMCQueueOf[asyncTemporaryQueueEvent]
+	asyncBufferSize           int64
+	asyncBufferReserved       atomic.Int64
+	asyncBufferWAL            *asyncBufferWAL
 }
 
 type mapBindToScanPointer map[string]func() any
@@ -296,6 +311,7 @@ func (e *entitySchema) init(registry *registry, entityType reflect.Type) error {
 	e.tableName = e.getTag("table", entityType.Name(), entityType.Name())
 	e.archived = e.getTag("archived", "true", "") == "true"
 	e.cacheAll = e.getTag("cacheAll", "true", "") == "true"
+	e.softDelete = e.getTag("softDelete", "true", "") == "true"
 	redisCacheName := e.getTag("redisCache", DefaultPoolCode, "")
 	if redisCacheName != "" {
 		_, has = registry.redisPools[redisCacheName]
@@ -374,6 +390,13 @@ func (e *entitySchema) init(registry *registry, entityType reflect.Type) error {
 
 	e.structureHash = strconv.FormatUint(uint64(h.Sum32()), 10)
 	e.columnMapping = columnMapping
+	err := e.initVersionColumn()
+	if err != nil {
+		return err
+	}
+	if _, has := columnMapping[fakeDeleteColumn]; has && e.softDelete {
+		return fmt.Errorf("field '%s' is reserved for soft delete in %s", fakeDeleteColumn, e.t.String())
+	}
 	localCacheLimit := e.getTag("localCache", "0", "")
 	if localCacheLimit != "" {
 		localCacheLimitAsInt, err := strconv.Atoi(localCacheLimit)
@@ -383,6 +406,26 @@ func (e *entitySchema) init(registry *registry, entityType reflect.Type) error {
 		e.hasLocalCache = true
 		e.localCacheLimit = localCacheLimitAsInt
 	}
+	localCacheTTL := e.getTag("localCacheTTL", "", "")
+	if localCacheTTL != "" {
+		ttl, err := time.ParseDuration(localCacheTTL)
+		if err != nil || ttl <= 0 {
+			return fmt.Errorf("invalid local cache TTL '%s'", localCacheTTL)
+		}
+		e.localCacheTTL = ttl
+	}
+	redisCacheTTL := e.getTag("redisCacheTTL", "", "")
+	if redisCacheTTL != "" {
+		ttl, err := time.ParseDuration(redisCacheTTL)
+		if err != nil || ttl < time.Second {
+			return fmt.Errorf("invalid redis cache TTL '%s'", redisCacheTTL)
+		}
+		e.redisCacheTTL = ttl
+	}
+	e.redisCacheTTLSliding = e.getTag("redisCacheTTLSliding", "true", "") == "true"
+	if e.redisCacheTTLSliding && e.redisCacheTTL == 0 {
+		return fmt.Errorf("redis cache TTL sliding requires redisCacheTTL in %s", e.t.String())
+	}
 	e.redisCacheName = redisCacheName
 	e.hasRedisCache = redisCacheName != ""
 	e.cacheKey = cacheKey
@@ -393,7 +436,12 @@ func (e *entitySchema) init(registry *registry, entityType reflect.Type) error {
 	} else if asyncGroup != "" {
 		e.asyncCacheKey = asyncGroup
 	}
-	e.asyncTemporaryQueue = xsync.NewMPMCQueueOf[asyncTemporaryQueueEvent](10000)
+	queueSize := asyncBufferQueueSize
+	if registry.asyncBuffer != nil && registry.asyncBuffer.QueueSize > 0 {
+		queueSize = registry.asyncBuffer.QueueSize
+	}
+	e.asyncTemporaryQueue = xsync.NewMPMCQueueOf[asyncTemporaryQueueEvent](queueSize)
+	e.asyncBufferSize = int64(queueSize)
 	e.uniqueIndexes = make(map[string]indexDefinition)
 	e.cachedIndexes = make(map[string]indexDefinition)
 	e.cachedUniqueIndexes = make(map[string]indexDefinition)
@@ -416,7 +464,11 @@ func (e *entitySchema) init(registry *registry, entityType reflect.Type) error {
 			e.cachedIndexes[indexName] = definition
 		}
 	}
-	err := e.validateIndexes(uniqueIndices, indices)
+	err = e.validateIndexes(uniqueIndices, indices)
+	if err != nil {
+		return err
+	}
+	err = e.initValidators()
 	if err != nil {
 		return err
 	}
@@ -496,6 +548,43 @@ func (e *entitySchema) validateIndexes(uniqueIndices map[string]map[int]string,
 	return nil
 }
 
+func (e *entitySchema) setRedisCacheTTL(p *RedisPipeLine, key string) {
+	if e.redisCacheTTL > 0 {
+		p.Expire(key, e.redisCacheTTL)
+	}
+}
+
+const redisCacheEntityUpdateScript = `
+local l = redis.call('LLEN', KEYS[1])
+for i = 1, #ARGV, 2 do
+	if l > tonumber(ARGV[i]) then
+		redis.call('LSET', KEYS[1], ARGV[i], ARGV[i + 1])
+	end
+end
+return 0
+`
+
+const redisCacheEntityFillScript = `
+if redis.call('EXISTS', KEYS[1]) == 0 then
+	redis.call('RPUSH', KEYS[1], unpack(ARGV))
+end
+return 0
+`
+
+func (e *entitySchema) updateRedisCacheEntity(p *RedisPipeLine, key string, bind Bind) {
+	args := make([]any, 0, len(bind)*2)
+	for column, value := range bind {
+		args = append(args, e.columnMapping[column]+1, convertBindValueToRedisValue(value))
+	}
+	p.Eval(redisCacheEntityUpdateScript, []string{key}, args...)
+}
+
+func (e *entitySchema) refreshRedisCacheTTL(p *RedisPipeLine, key string) {
+	if e.redisCacheTTLSliding {
+		p.Expire(key, e.redisCacheTTL)
+	}
+}
+
 func (e *entitySchema) getTag(key, trueValue, defaultValue string) string {
 	userValue, has := e.tags["ID"][key]
 	if has {
@@ -655,6 +744,7 @@ func (e *entitySchema) search(orm ORM, where Where, pager *Pager, withCount bool
 		}
 		return &localCacheIDsAnonymousIterator{c: orm.(*ormImplementation), schema: schema, ids: ids, index: -1}, total
 	}
+	where = schema.excludeDeleted(where)
 	whereQuery := where.String()
 	query := "SELECT " + schema.fieldsQuery + " FROM `" + schema.GetTableName() + "` WHERE " + whereQuery
 	if pager != nil {
@@ -864,6 +954,19 @@ func (e *entitySchema) buildReferenceField(attributes schemaFieldAttributes) {
 	if attributes.IsArray {
 		fType = fType.Elem()
 	}
+	onDelete := attributes.Tags["onDelete"]
+	switch onDelete {
+	case "", onDeleteCascade, onDeleteRestrict:
+	case onDeleteSetNull:
+		if attributes.Tags["required"] == "true" {
+			panic(fmt.Errorf("%s field %s with onDelete=%s can't be required", e.t.String(), attributes.Field.Name, onDelete))
+		}
+	default:
+		panic(fmt.Errorf("%s field %s has invalid onDelete value '%s'", e.t.String(), attributes.Field.Name, onDelete))
+	}
+	if onDelete != "" && attributes.IsArray {
+		panic(fmt.Errorf("%s field %s onDelete is not supported in array", e.t.String(), attributes.Field.Name))
+	}
 	for i, columnName := range attributes.GetColumnNames() {
 		isRequired := attributes.Tags["required"] == "true"
 		if attributes.IsArray {
@@ -878,8 +981,9 @@ func (e *entitySchema) buildReferenceField(attributes schemaFieldAttributes) {
 		if i == 0 {
 			refType = reflect.New(fType).Interface().(referenceInterface).getType()
 			def := referenceDefinition{
-				Cached: attributes.Tags["cached"] == "true",
-				Type:   refType,
+				Cached:   attributes.Tags["cached"] == "true",
+				Type:     refType,
+				OnDelete: onDelete,
 			}
 			if def.Cached {
 				e.cachedReferences[columnName] = def
@@ -1418,11 +1522,11 @@ func extractTags(registry *registry, entityType reflect.Type, prefix string) (fi
 func extractTag(registry *registry, field reflect.StructField) map[string]map[string]string {
 	tag, ok := field.Tag.Lookup("orm")
 	if ok {
-		args := strings.Split(tag, ";")
+		args := splitTag(tag)
 		length := len(args)
 		var attributes = make(map[string]string, length)
 		for j := 0; j < length; j++ {
-			arg := strings.Split(args[j], "=")
+			arg := strings.SplitN(args[j], "=", 2)
 			if len(arg) == 1 {
 				attributes[arg[0]] = "true"
 			} else {
@@ -1443,6 +1547,26 @@ func extractTag(registry *registry, field reflect.StructField) map[string]map[st
 	return make(map[string]map[string]string)
 }
 
+// splitTag splits orm tag by ";", escaped `\;` is kept as ";" inside the value, for example in regex
+func splitTag(tag string) []string {
+	var args []string
+	current := strings.Builder{}
+	for i := 0; i < len(tag); i++ {
+		if tag[i] == '\\' && i+1 < len(tag) && tag[i+1] == ';' {
+			current.WriteByte(';')
+			i++
+			continue
+		}
+		if tag[i] == ';' {
+			args = append(args, current.String())
+			current.Reset()
+			continue
+		}
+		current.WriteByte(tag[i])
+	}
+	return append(args, current.String())
+}
+
 func (fields *tableFields) buildColumnNames(subFieldPrefix string) ([]string, string) {
 	fieldsQuery := ""
 	columns := make([]string, 0)
@@ -1,14 +1,12 @@
 package beeorm
 
 import (
+	"errors"
 	"fmt"
 	"strconv"
 	"strings"
-	"time"
 
 	"github.com/puzpuzpuz/xsync/v2"
-
-	jsoniter "github.com/json-iterator/go"
 )
 
 type entitySQLOperations map[FlushType][]EntityFlush
@@ -31,7 +29,110 @@ func (orm *ormImplementation) flush(async bool) error {
 	if orm.trackedEntities == nil || orm.trackedEntities.Size() == 0 {
 		return nil
 	}
-	sqlGroup := orm.groupSQLOperations()
+	rollbackReferenceActions, err := orm.handleReferenceActions()
+	if err != nil {
+		return err
+	}
+	err = orm.buildFlushActions(async)
+	var reserved map[*entitySchema]int64
+	if err == nil {
+		reserved, err = orm.reserveAsyncBuffer()
+	}
+	if err != nil {
+		rollbackReferenceActions()
+		orm.flushDBActions = nil
+		orm.flushPostActions = orm.flushPostActions[0:0]
+		orm.redisPipeLines = nil
+		orm.asyncEventsToReserve = nil
+		return err
+	}
+	if !orm.inTransaction {
+		defer orm.releaseAsyncBuffer(reserved)
+	}
+	if !async {
+		err = orm.executeDBActionsWithRetry()
+	}
+	if err == nil && orm.inTransaction {
+		for _, pipeline := range orm.redisPipeLines {
+			orm.transactionPipeLines = append(orm.transactionPipeLines, pipeline)
+		}
+		orm.transactionPostActions = append(orm.transactionPostActions, orm.flushPostActions...)
+	} else if err == nil {
+		for _, pipeline := range orm.redisPipeLines {
+			pipeline.Exec(orm)
+		}
+		for _, action := range orm.flushPostActions {
+			action(orm)
+		}
+	} else {
+		orm.releaseAsyncBuffer(reserved)
+		var conflict *VersionConflictError
+		if errors.As(err, &conflict) {
+			invalidateEntityCache(orm, conflict.Schema.(*entitySchema), conflict.ID)
+		}
+	}
+	orm.trackedEntities.Clear()
+	orm.flushDBActions = nil
+	orm.flushPostActions = orm.flushPostActions[0:0]
+	orm.redisPipeLines = nil
+	return err
+}
+
+func (orm *ormImplementation) executeDBActions() (err error) {
+	var transactions []DBTransaction
+	defer func() {
+		for _, tx := range transactions {
+			tx.Rollback(orm)
+		}
+		if rec := recover(); rec != nil {
+			asErr, isErr := rec.(error)
+			if isErr {
+				err = asErr
+				return
+			}
+			err = fmt.Errorf("%v", rec)
+		}
+	}()
+	orm.flushDBCommitActions = nil
+	for code, actions := range orm.flushDBActions {
+		var d DBBase
+		d = orm.Engine().DB(code)
+		if orm.inTransaction {
+			d = orm.getDBTransaction(d.(*dbImplementation))
+		} else if len(actions) > 1 || len(orm.flushDBActions) > 1 {
+			tx := d.(DB).Begin(orm)
+			transactions = append(transactions, tx)
+			d = tx
+		}
+		for _, action := range actions {
+			action(d)
+		}
+	}
+	for _, tx := range transactions {
+		tx.Commit(orm)
+	}
+	transactions = nil
+	orm.runDBCommitActions()
+	return nil
+}
+
+func (orm *ormImplementation) appendDBCommitAction(action func()) {
+	orm.flushDBCommitActions = append(orm.flushDBCommitActions, action)
+}
+
+func (orm *ormImplementation) runDBCommitActions() {
+	for _, action := range orm.flushDBCommitActions {
+		action()
+	}
+	orm.flushDBCommitActions = nil
+}
+
+func (orm *ormImplementation) buildFlushActions(async bool) error {
+	sqlGroup, err := orm.groupSQLOperations()
+	if err != nil {
+		return err
+	}
+	var invalid []*FieldValidationError
 	for _, operations := range sqlGroup {
 		for schema, queryOperations := range operations {
 			deletes, has := queryOperations[Delete]
@@ -44,64 +145,20 @@ func (orm *ormImplementation) flush(async bool) error {
 			inserts, has := queryOperations[Insert]
 			if has {
 				err := orm.handleInserts(async, schema, inserts)
-				if err != nil {
+				if err != nil && !collectValidationErrors(&invalid, err) {
 					return err
 				}
 			}
 			updates, has := queryOperations[Update]
 			if has {
 				err := orm.handleUpdates(async, schema, updates)
-				if err != nil {
+				if err != nil && !collectValidationErrors(&invalid, err) {
 					return err
 				}
 			}
 		}
 	}
-	var err error
-	if !async {
-		func() {
-			var transactions []DBTransaction
-			defer func() {
-				for _, tx := range transactions {
-					tx.Rollback(orm)
-				}
-				if rec := recover(); rec != nil {
-					asErr, isErr := rec.(error)
-					if isErr {
-						err = asErr
-						return
-					}
-					err = fmt.Errorf("%v", rec)
-				}
-			}()
-			for code, actions := range orm.flushDBActions {
-				var d DBBase
-				d = orm.Engine().DB(code)
-				if len(actions) > 1 || len(orm.flushDBActions) > 1 {
-					tx := d.(DB).Begin(orm)
-					transactions = append(transactions, tx)
-					d = tx
-				}
-				for _, action := range actions {
-					action(d)
-				}
-			}
-			for _, tx := range transactions {
-				tx.Commit(orm)
-			}
-		}()
-	}
-	for _, pipeline := range orm.redisPipeLines {
-		pipeline.Exec(orm)
-	}
-	for _, action := range orm.flushPostActions {
-		action(orm)
-	}
-	orm.trackedEntities.Clear()
-	orm.flushDBActions = nil
-	orm.flushPostActions = orm.flushPostActions[0:0]
-	orm.redisPipeLines = nil
-	return err
+	return newValidationError(invalid)
 }
 
 func (orm *ormImplementation) ClearFlush() {
@@ -111,34 +168,37 @@ func (orm *ormImplementation) ClearFlush() {
 	orm.flushDBActions = nil
 	orm.flushPostActions = orm.flushPostActions[0:0]
 	orm.redisPipeLines = nil
+	orm.asyncEventsToReserve = nil
 }
 
 func (orm *ormImplementation) handleDeletes(async bool, schema *entitySchema, operations []EntityFlush) error {
-	var args []any
-	if !async {
-		args = make([]any, len(operations))
-	}
-	sql := "DELETE FROM `" + schema.GetTableName() + "` WHERE ID IN ("
-	if async {
-		for i, operation := range operations {
-			if i > 0 {
-				sql += ","
+	deleteQuery := "DELETE FROM `" + schema.GetTableName() + "`"
+	if schema.softDelete {
+		var forced, soft []EntityFlush
+		for _, operation := range operations {
+			if operation.(*removableEntity).force {
+				forced = append(forced, operation)
+			} else {
+				soft = append(soft, operation)
 			}
-			sql += strconv.FormatUint(operation.ID(), 10)
 		}
-	} else {
-		sql += "?" + strings.Repeat(",?", len(operations)-1)
-	}
-	sql += ")"
-	if !async {
-		for i, operation := range operations {
-			args[i] = operation.ID()
+		if len(forced) > 0 {
+			err := orm.appendDeleteQuery(async, schema, deleteQuery, forced)
+			if err != nil {
+				return err
+			}
+		}
+		if len(soft) > 0 {
+			err := orm.appendDeleteQuery(async, schema, "UPDATE `"+schema.GetTableName()+"` SET `"+fakeDeleteColumn+"` = `ID`", soft)
+			if err != nil {
+				return err
+			}
 		}
-		orm.appendDBAction(schema, func(db DBBase) {
-			db.Exec(orm, sql, args...)
-		})
 	} else {
-		publishAsyncEvent(schema, []any{sql})
+		err := orm.appendDeleteQuery(async, schema, deleteQuery, operations)
+		if err != nil {
+			return err
+		}
 	}
 
 	lc, hasLocalCache := schema.GetLocalCache()
@@ -163,7 +223,7 @@ func (orm *ormImplementation) handleDeletes(async bool, schema *entitySchema, op
 		}
 		if hasLocalCache {
 			orm.flushPostActions = append(orm.flushPostActions, func(_ ORM) {
-				lc.setEntity(orm, operation.ID(), nil)
+				lc.updateEntity(orm, operation.ID(), nil)
 			})
 		}
 		rc, hasRedisCache := schema.GetRedisCache()
@@ -171,6 +231,7 @@ func (orm *ormImplementation) handleDeletes(async bool, schema *entitySchema, op
 			cacheKey := schema.getCacheKey() + ":" + strconv.FormatUint(operation.ID(), 10)
 			orm.RedisPipeLine(rc.GetCode()).Del(cacheKey)
 			orm.RedisPipeLine(rc.GetCode()).LPush(cacheKey, "")
+			schema.setRedisCacheTTL(orm.RedisPipeLine(rc.GetCode()), cacheKey)
 		}
 		for columnName := range schema.cachedReferences {
 			if bind == nil {
@@ -226,26 +287,13 @@ func (orm *ormImplementation) handleDeletes(async bool, schema *entitySchema, op
 		}
 		logTableSchema, hasLogTable := orm.engine.registry.entityLogSchemas[schema.t]
 		if hasLogTable {
-			data := make([]any, 6)
-			data[0] = "INSERT INTO `" + logTableSchema.tableName + "`(ID,EntityID,Date,Meta,`Before`) VALUES(?,?,?,?,?)"
-			data[1] = strconv.FormatUint(logTableSchema.uuid(orm), 10)
-			data[2] = strconv.FormatUint(operation.ID(), 10)
-			data[3] = time.Now().Format(time.DateTime)
-			if len(orm.meta) > 0 {
-				asJSON, _ := jsoniter.ConfigFastest.MarshalToString(orm.meta)
-				data[4] = asJSON
-			} else {
-				data[4] = nil
-			}
 			if bind == nil {
 				bind, err = deleteFlush.getOldBind()
 				if err != nil {
 					return err
 				}
 			}
-			asJSON, _ := jsoniter.ConfigFastest.MarshalToString(bind)
-			data[5] = asJSON
-			publishAsyncEvent(logTableSchema, data)
+			orm.publishAsyncEventAfterFlush(logTableSchema, orm.newLogTableEvent(schema, logTableSchema, operation.ID(), bind, nil))
 		}
 		for _, p := range orm.engine.pluginFlush {
 			if bind == nil {
@@ -258,31 +306,81 @@ func (orm *ormImplementation) handleDeletes(async bool, schema *entitySchema, op
 			if err != nil {
 				return err
 			}
-			if after != nil {
+			if after != nil && orm.plan == nil {
 				orm.flushPostActions = append(orm.flushPostActions, after)
 			}
 		}
+		orm.appendAfterFlushHook(deleteFlush.getValue().Addr().Interface(), Delete)
+	}
+	return nil
+}
+
+func (orm *ormImplementation) appendDeleteQuery(async bool, schema *entitySchema, query string, operations []EntityFlush) error {
+	if async {
+		for _, operation := range operations {
+			bind, err := operation.(entityFlushDelete).getOldBind()
+			if err != nil {
+				return err
+			}
+			event := orm.newAsyncEvent(schema, Delete, operation.ID(), bind, nil)
+			event.Force = operation.(*removableEntity).force
+			orm.publishAsyncEventAfterFlush(schema, asyncTemporaryQueueEvent{event})
+		}
+		return nil
+	}
+	args := make([]any, len(operations))
+	for i, operation := range operations {
+		args[i] = operation.ID()
+	}
+	sql := query + " WHERE ID IN (?" + strings.Repeat(",?", len(operations)-1) + ")"
+	orm.appendDBAction(schema, func(db DBBase) {
+		db.Exec(orm, sql, args...)
+	})
+	if schema.hasSubscribers() {
+		flushed := make([]*flushedEvent, len(operations))
+		for i, operation := range operations {
+			bind, err := operation.(entityFlushDelete).getOldBind()
+			if err != nil {
+				return err
+			}
+			flushed[i] = &flushedEvent{schema: schema, flushType: Delete, id: operation.ID(), before: bind}
+		}
+		orm.appendFlushedEvents(flushed...)
 	}
 	return nil
 }
 
 func (orm *ormImplementation) handleInserts(async bool, schema *entitySchema, operations []EntityFlush) error {
+	var invalid []*FieldValidationError
+	operations, err := orm.handleUpserts(async, schema, operations)
+	if err != nil && !collectValidationErrors(&invalid, err) {
+		return err
+	}
+	if len(operations) == 0 {
+		return newValidationError(invalid)
+	}
 	columns := schema.GetColumns()
 	sql := "INSERT INTO `" + schema.GetTableName() + "`(`ID`"
 	for _, column := range columns[1:] {
 		sql += ",`" + column + "`"
 	}
 	sql += ") VALUES"
-	var args []any
+	var rows [][]any
 	if !async {
-		args = make([]any, 0, len(operations)*len(columns))
+		rows = make([][]any, 0, len(operations))
 	}
 	lc, hasLocalCache := schema.GetLocalCache()
 	rc, hasRedisCache := schema.GetRedisCache()
-	for i, operation := range operations {
+	for _, operation := range operations {
 		insert := operation.(entityFlushInsert)
 		bind, err := insert.getBind()
+		if err == nil {
+			err = insert.validate(bind)
+		}
 		if err != nil {
+			if collectValidationErrors(&invalid, err) {
+				continue
+			}
 			return err
 		}
 		if len(orm.engine.pluginFlush) > 0 {
@@ -292,7 +390,7 @@ func (orm *ormImplementation) handleInserts(async bool, schema *entitySchema, op
 				if err != nil {
 					return err
 				}
-				if after != nil {
+				if after != nil && orm.plan == nil {
 					orm.flushPostActions = append(orm.flushPostActions, after)
 				}
 			}
@@ -309,70 +407,26 @@ func (orm *ormImplementation) handleInserts(async bool, schema *entitySchema, op
 				orm.RedisPipeLine(cache.GetConfig().GetCode()).HSet(hSetKey, hField, strconv.FormatUint(insert.ID(), 10))
 			}
 		}
-		var asyncData []any
 		if async {
-			asyncData = make([]any, len(columns)+1)
-		}
-
-		if i > 0 && !async {
-			sql += ","
-		}
-		if !async || i == 0 {
-			sql += "(?"
-		}
-		if !async {
-			args = append(args, bind["ID"])
+			orm.publishAsyncEventAfterFlush(schema, asyncTemporaryQueueEvent{orm.newAsyncEvent(schema, Insert, insert.ID(), nil, bind)})
 		} else {
-			if async {
-				asyncData[1] = strconv.FormatUint(bind["ID"].(uint64), 10)
-			} else {
-				asyncData[1] = bind["ID"]
-			}
-		}
-		for j, column := range columns[1:] {
-			v := bind[column]
-			if async {
-				vAsUint64, isUint64 := v.(uint64)
-				if isUint64 {
-					v = strconv.FormatUint(vAsUint64, 10)
-				}
-			}
-			if !async {
-				args = append(args, v)
-			} else {
-				asyncData[j+2] = v
+			args := make([]any, 0, len(columns))
+			args = append(args, bind["ID"])
+			for _, column := range columns[1:] {
+				args = append(args, bind[column])
 			}
-			if !async || i == 0 {
-				sql += ",?"
+			rows = append(rows, args)
+			if schema.hasSubscribers() {
+				orm.appendFlushedEvents(&flushedEvent{schema: schema, flushType: Insert, id: insert.ID(), after: bind})
 			}
 		}
-		if !async || i == 0 {
-			sql += ")"
-		}
-		if async {
-			asyncData[0] = sql
-			publishAsyncEvent(schema, asyncData)
-		}
 		logTableSchema, hasLogTable := orm.engine.registry.entityLogSchemas[schema.t]
 		if hasLogTable {
-			data := make([]any, 6)
-			data[0] = "INSERT INTO `" + logTableSchema.tableName + "`(ID,EntityID,Date,Meta,`After`) VALUES(?,?,?,?,?)"
-			data[1] = strconv.FormatUint(logTableSchema.uuid(orm), 10)
-			data[2] = strconv.FormatUint(bind["ID"].(uint64), 10)
-			data[3] = time.Now().Format(time.DateTime)
-			if len(orm.meta) > 0 {
-				asJSON, _ := jsoniter.ConfigFastest.MarshalToString(orm.meta)
-				data[4] = asJSON
-			} else {
-				data[4] = nil
-			}
-			asJSON, _ := jsoniter.ConfigFastest.MarshalToString(bind)
-			data[5] = asJSON
-			publishAsyncEvent(logTableSchema, data)
+			orm.publishAsyncEventAfterFlush(logTableSchema, orm.newLogTableEvent(schema, logTableSchema, bind["ID"].(uint64), nil, bind))
 		}
 		if hasLocalCache {
 			orm.flushPostActions = append(orm.flushPostActions, func(_ ORM) {
-				lc.setEntity(orm, insert.ID(), insert.getEntity())
+				lc.updateEntity(orm, insert.ID(), insert.getEntity())
 			})
 		}
 		for columnName := range schema.cachedReferences {
@@ -388,6 +442,7 @@ func (orm *ormImplementation) handleInserts(async bool, schema *entitySchema, op
 			}
 			redisSetKey := schema.cacheKey + ":" + refColumn + ":" + strconv.FormatUint(id.(uint64), 10)
 			orm.RedisPipeLine(schema.getForcedRedisCode()).SAdd(redisSetKey, strconv.FormatUint(insert.ID(), 10))
+			schema.setRedisCacheTTL(orm.RedisPipeLine(schema.getForcedRedisCode()), redisSetKey)
 		}
 		if schema.cacheAll {
 			if schema.hasLocalCache {
@@ -397,6 +452,7 @@ func (orm *ormImplementation) handleInserts(async bool, schema *entitySchema, op
 			}
 			redisSetKey := schema.cacheKey + ":" + cacheAllFakeReferenceKey
 			orm.RedisPipeLine(schema.getForcedRedisCode()).SAdd(redisSetKey, strconv.FormatUint(insert.ID(), 10))
+			schema.setRedisCacheTTL(orm.RedisPipeLine(schema.getForcedRedisCode()), redisSetKey)
 		}
 		for indexName, def := range schema.cachedIndexes {
 			indexAttributes := make([]any, len(def.Columns))
@@ -412,28 +468,93 @@ func (orm *ormImplementation) handleInserts(async bool, schema *entitySchema, op
 			}
 			redisSetKey := schema.cacheKey + ":" + key + ":" + strconv.FormatUint(id, 10)
 			orm.RedisPipeLine(schema.getForcedRedisCode()).SAdd(redisSetKey, strconv.FormatUint(insert.ID(), 10))
+			schema.setRedisCacheTTL(orm.RedisPipeLine(schema.getForcedRedisCode()), redisSetKey)
 		}
 		if hasRedisCache {
-			idAsString := strconv.FormatUint(bind["ID"].(uint64), 10)
-			orm.RedisPipeLine(rc.GetCode()).RPush(schema.getCacheKey()+":"+idAsString, convertBindToRedisValue(bind, schema)...)
+			cacheKey := schema.getCacheKey() + ":" + strconv.FormatUint(bind["ID"].(uint64), 10)
+			orm.RedisPipeLine(rc.GetCode()).RPush(cacheKey, convertBindToRedisValue(bind, schema)...)
+			schema.setRedisCacheTTL(orm.RedisPipeLine(rc.GetCode()), cacheKey)
 		}
+		orm.appendAfterFlushHook(insert.getEntity(), Insert)
+	}
+	if len(invalid) > 0 {
+		return newValidationError(invalid)
 	}
 	if !async {
-		orm.appendDBAction(schema, func(db DBBase) {
-			db.Exec(orm, sql, args...)
-		})
+		orm.appendInsertDBActions(schema, sql, rows)
 	}
 
 	return nil
 }
 
+const mySQLMaxPlaceholders = 65535
+
+func (orm *ormImplementation) appendInsertDBActions(schema *entitySchema, query string, rows [][]any) {
+	placeholders := "(?" + strings.Repeat(",?", len(rows[0])-1) + ")"
+	maxRows := 0
+	maxSize := 0
+	if len(rows) > 1 {
+		db := schema.GetDB().(*dbImplementation)
+		maxRows = db.GetConfig().GetOptions().MaxInsertRows
+		if limit := mySQLMaxPlaceholders / len(rows[0]); maxRows == 0 || maxRows > limit {
+			maxRows = limit
+		}
+		maxSize = db.getMaxAllowedPacket()
+		maxSize -= maxSize / 10
+	}
+	start := 0
+	size := len(query)
+	for i, row := range rows {
+		rowSize := len(placeholders) + 1
+		for _, value := range row {
+			rowSize += estimateQueryValueSize(value)
+		}
+		if i > start && ((maxRows > 0 && i-start >= maxRows) || (maxSize > 0 && size+rowSize > maxSize)) {
+			orm.appendInsertDBAction(schema, query, placeholders, rows[start:i])
+			start = i
+			size = len(query)
+		}
+		size += rowSize
+	}
+	orm.appendInsertDBAction(schema, query, placeholders, rows[start:])
+}
+
+func (orm *ormImplementation) appendInsertDBAction(schema *entitySchema, query, placeholders string, rows [][]any) {
+	sql := query + placeholders + strings.Repeat(","+placeholders, len(rows)-1)
+	args := make([]any, 0, len(rows)*len(rows[0]))
+	for _, row := range rows {
+		args = append(args, row...)
+	}
+	orm.appendDBAction(schema, func(db DBBase) {
+		db.Exec(orm, sql, args...)
+	})
+}
+
+func estimateQueryValueSize(value any) int {
+	switch v := value.(type) {
+	case string:
+		return len(v) + 2
+	case []byte:
+		return len(v) + 2
+	default:
+		return 20
+	}
+}
+
 func (orm *ormImplementation) handleUpdates(async bool, schema *entitySchema, operations []EntityFlush) error {
 	var queryPrefix string
+	var invalid []*FieldValidationError
 	for _, operation := range operations {
 		update := operation.(entityFlushUpdate)
 		newBind, oldBind, forcedNew, forcedOld, err := update.getBind()
 		elem := update.getValue().Elem()
+		if err == nil && len(newBind) > 0 {
+			err = update.validate(newBind)
+		}
 		if err != nil {
+			if collectValidationErrors(&invalid, err) {
+				continue
+			}
 			return err
 		}
 		if len(newBind) == 0 {
@@ -445,11 +566,23 @@ func (orm *ormImplementation) handleUpdates(async bool, schema *entitySchema, op
 				if err != nil {
 					return err
 				}
-				if after != nil {
+				if after != nil && orm.plan == nil {
 					orm.flushPostActions = append(orm.flushPostActions, after)
 				}
 			}
 		}
+		version := uint64(0)
+		var setVersion func()
+		if schema.versionColumn != "" {
+			version = schema.getVersion(elem)
+			newBind[schema.versionColumn] = version + 1
+			oldBind[schema.versionColumn] = version
+			if update.getEntity() != nil && orm.plan == nil {
+				setVersion = func() {
+					schema.fieldSetters[schema.versionColumn](version+1, elem)
+				}
+			}
+		}
 		if len(schema.cachedUniqueIndexes) > 0 {
 			cache := orm.Engine().Redis(schema.getForcedRedisCode())
 			for indexName, definition := range schema.cachedUniqueIndexes {
@@ -476,100 +609,119 @@ func (orm *ormImplementation) handleUpdates(async bool, schema *entitySchema, op
 			}
 		}
 
-		if queryPrefix == "" {
-			queryPrefix = "UPDATE `" + schema.GetTableName() + "` SET "
+		var increments, fetched Bind
+		if fields, isFields := update.(*editableFields); isFields {
+			increments = fields.increments
 		}
-		sql := queryPrefix
-		k := 0
-		var args []any
-		var asyncArgs []any
 		if async {
-			asyncArgs = make([]any, len(newBind)+2)
-		} else {
-			args = make([]any, len(newBind)+1)
-		}
-		for column, value := range newBind {
-			if k > 0 {
-				sql += ","
+			event := orm.newAsyncEvent(schema, Update, update.ID(), oldBind, newBind)
+			event.Increments = encodeAsyncBind(increments)
+			orm.publishAsyncEventAfterFlush(schema, asyncTemporaryQueueEvent{event})
+			if setVersion != nil {
+				orm.flushPostActions = append(orm.flushPostActions, func(_ ORM) {
+					setVersion()
+				})
 			}
-			sql += "`" + column + "`=?"
-			if async {
-				asUint64, isUint64 := value.(uint64)
-				if isUint64 {
-					value = strconv.FormatUint(asUint64, 10)
+		} else {
+			if queryPrefix == "" {
+				queryPrefix = "UPDATE `" + schema.GetTableName() + "` SET "
+			}
+			sql := queryPrefix
+			k := 0
+			argsLen := len(newBind) + 1
+			if schema.versionColumn != "" {
+				argsLen++
+			}
+			args := make([]any, argsLen)
+			for column, value := range newBind {
+				if k > 0 {
+					sql += ","
+				}
+				delta, isIncrement := increments[column]
+				if isIncrement {
+					sql += "`" + column + "`=`" + column + "`+?"
+					value = delta
+				} else {
+					sql += "`" + column + "`=?"
 				}
-				asyncArgs[k+1] = value
-			} else {
 				args[k] = value
+				k++
 			}
-			k++
-		}
-		sql += " WHERE ID = ?"
-		if async {
-			asyncArgs[k+1] = strconv.FormatUint(update.ID(), 10)
-		} else {
+			sql += " WHERE ID = ?"
 			args[k] = update.ID()
-		}
-		if async {
-			asyncArgs[0] = sql
-			publishAsyncEvent(schema, asyncArgs)
-		} else {
-			orm.appendDBAction(schema, func(db DBBase) {
-				db.Exec(orm, sql, args...)
-			})
+			if schema.versionColumn != "" {
+				sql += " AND `" + schema.versionColumn + "` = ?"
+				args[k+1] = version
+				id := update.ID()
+				orm.appendDBAction(schema, func(db DBBase) {
+					if db.Exec(orm, sql, args...).RowsAffected() == 0 {
+						panic(&VersionConflictError{Schema: schema, ID: id, Version: version})
+					}
+					if setVersion != nil {
+						orm.appendDBCommitAction(setVersion)
+					}
+				})
+			} else {
+				orm.appendDBAction(schema, func(db DBBase) {
+					db.Exec(orm, sql, args...)
+				})
+			}
+			if len(increments) > 0 && orm.plan == nil {
+				orm.appendIncrementsFetchDBAction(schema, update, increments, &fetched)
+			}
+			if schema.hasSubscribers() {
+				orm.appendFlushedEvents(&flushedEvent{schema: schema, flushType: Update, id: update.ID(), before: oldBind, after: newBind})
+			}
 		}
 
 		logTableSchema, hasLogTable := orm.engine.registry.entityLogSchemas[schema.t]
 		if hasLogTable {
-			data := make([]any, 7)
-			data[0] = "INSERT INTO `" + logTableSchema.tableName + "`(ID,EntityID,Date,Meta,`Before`,`After`) VALUES(?,?,?,?,?,?)"
-			data[1] = strconv.FormatUint(logTableSchema.uuid(orm), 10)
-			data[2] = strconv.FormatUint(update.ID(), 10)
-			data[3] = time.Now().Format(time.DateTime)
-			if len(orm.meta) > 0 {
-				asJSON, _ := jsoniter.ConfigFastest.MarshalToString(orm.meta)
-				data[4] = asJSON
-			} else {
-				data[4] = nil
-			}
-			asJSON, _ := jsoniter.ConfigFastest.MarshalToString(oldBind)
-			data[5] = asJSON
-			asJSON, _ = jsoniter.ConfigFastest.MarshalToString(newBind)
-			data[6] = asJSON
-			publishAsyncEvent(logTableSchema, data)
+			orm.publishAsyncEventAfterFlush(logTableSchema, orm.newLogTableEvent(schema, logTableSchema, update.ID(), oldBind, newBind))
 		}
 
 		if update.getEntity() == nil {
-			for field, newValue := range newBind {
-				fSetter := schema.fieldSetters[field]
+			orm.flushPostActions = append(orm.flushPostActions, func(_ ORM) {
+				if orm.plan != nil {
+					return
+				}
 				if schema.hasLocalCache {
+					schema.localCache.mutex.Lock()
+					defer schema.localCache.mutex.Unlock()
+				}
+				for field, newValue := range newBind {
+					schema.fieldSetters[field](newValue, elem)
+				}
+				for field, value := range fetched {
+					schema.fieldSetters[field](value, elem)
+				}
+				if schema.hasLocalCache {
+					schema.localCache.publishInvalidation(orm, "", update.ID())
+				}
+			})
+		} else if update.getEntity() != nil && schema.hasLocalCache {
+			orm.flushPostActions = append(orm.flushPostActions, func(_ ORM) {
+				sourceValue := update.getSourceValue()
+				if orm.plan == nil {
 					func() {
 						schema.localCache.mutex.Lock()
 						defer schema.localCache.mutex.Unlock()
-						fSetter(newValue, elem)
+						copyEntity(update.getValue().Elem(), sourceValue.Elem(), schema.fields, true)
 					}()
-				} else {
-					fSetter(newValue, elem)
 				}
-			}
-		} else if schema.hasLocalCache {
-			orm.flushPostActions = append(orm.flushPostActions, func(_ ORM) {
-				sourceValue := update.getSourceValue()
-				func() {
-					schema.localCache.mutex.Lock()
-					defer schema.localCache.mutex.Unlock()
-					copyEntity(update.getValue().Elem(), sourceValue.Elem(), schema.fields, true)
-				}()
-				schema.localCache.setEntity(orm, operation.ID(), update.getEntity())
+				schema.localCache.updateEntity(orm, operation.ID(), update.getEntity())
 			})
 		}
 
 		if schema.hasRedisCache {
 			p := orm.RedisPipeLine(schema.redisCache.GetCode())
 			rKey := schema.getCacheKey() + ":" + strconv.FormatUint(update.ID(), 10)
-			for column, val := range newBind {
-				index := int64(schema.columnMapping[column] + 1)
-				p.LSet(rKey, index, convertBindValueToRedisValue(val))
+			if schema.redisCacheTTL > 0 {
+				schema.updateRedisCacheEntity(p, rKey, newBind)
+			} else {
+				for column, val := range newBind {
+					index := int64(schema.columnMapping[column] + 1)
+					p.LSet(rKey, index, convertBindValueToRedisValue(val))
+				}
 			}
 		}
 		for columnName := range schema.cachedReferences {
@@ -605,6 +757,7 @@ func (orm *ormImplementation) handleUpdates(async bool, schema *entitySchema, op
 				}
 				redisSetKey := schema.cacheKey + ":" + refColumn + ":" + strconv.FormatUint(newAsInt, 10)
 				orm.RedisPipeLine(schema.getForcedRedisCode()).SAdd(redisSetKey, strconv.FormatUint(update.ID(), 10))
+				schema.setRedisCacheTTL(orm.RedisPipeLine(schema.getForcedRedisCode()), redisSetKey)
 			}
 		}
 		for indexName, def := range schema.cachedIndexes {
@@ -638,6 +791,7 @@ func (orm *ormImplementation) handleUpdates(async bool, schema *entitySchema, op
 			redisSetKey := schema.cacheKey + ":" + key + ":" + strconv.FormatUint(id, 10)
 			idAsString := strconv.FormatUint(update.ID(), 10)
 			orm.RedisPipeLine(schema.getForcedRedisCode()).SAdd(redisSetKey, idAsString)
+			schema.setRedisCacheTTL(orm.RedisPipeLine(schema.getForcedRedisCode()), redisSetKey)
 
 			indexAttributes = indexAttributes[0:len(def.Columns)]
 			for j, indexColumn := range def.Columns {
@@ -657,14 +811,24 @@ func (orm *ormImplementation) handleUpdates(async bool, schema *entitySchema, op
 			redisSetKey = schema.cacheKey + ":" + key2 + ":" + strconv.FormatUint(id2, 10)
 			orm.RedisPipeLine(schema.getForcedRedisCode()).SRem(redisSetKey, idAsString)
 		}
+		orm.appendAfterFlushHook(update.getValue().Interface(), Update)
 	}
-	return nil
+	return newValidationError(invalid)
 }
 
-func (orm *ormImplementation) groupSQLOperations() sqlOperations {
+func (orm *ormImplementation) groupSQLOperations() (sqlOperations, error) {
 	sqlGroup := make(sqlOperations)
+	var err error
 	orm.trackedEntities.Range(func(_ uint64, value *xsync.MapOf[uint64, EntityFlush]) bool {
 		value.Range(func(_ uint64, flush EntityFlush) bool {
+			if orm.plan != nil {
+				orm.plan.addBeforeFlushHook(flush)
+			} else {
+				err = runBeforeFlushHook(orm, flush)
+				if err != nil {
+					return false
+				}
+			}
 			schema := flush.Schema()
 			db := orm.engine.DB(schema.mysqlPoolCode)
 			poolSQLGroup, has := sqlGroup[db]
@@ -680,9 +844,16 @@ func (orm *ormImplementation) groupSQLOperations() sqlOperations {
 			tableSQLGroup[flush.flushType()] = append(tableSQLGroup[flush.flushType()], flush)
 			return true
 		})
-		return true
+		return err == nil
+	})
+	return sqlGroup, err
+}
+
+func (orm *ormImplementation) publishAsyncEventAfterFlush(schema *entitySchema, event asyncTemporaryQueueEvent) {
+	orm.countAsyncEvents(schema, 1)
+	orm.flushPostActions = append(orm.flushPostActions, func(_ ORM) {
+		orm.enqueueAsyncEvent(schema, event)
 	})
-	return sqlGroup
 }
 
 func (orm *ormImplementation) appendDBAction(schema EntitySchema, action dbAction) {
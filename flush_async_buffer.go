@@ -1,46 +1,161 @@
 package beeorm
 
 import (
+	"errors"
 	"fmt"
 	"sync"
 	"time"
-
-	jsoniter "github.com/json-iterator/go"
 )
 
 const redisRPushPackSize = 1000
+const redisRPushPackMaxBytes = 8 << 20
+const asyncBufferQueueSize = 10000
+const asyncBufferDrainTimeout = time.Second * 10
+
+var ErrAsyncBufferFull = errors.New("async buffer is full")
 
 type asyncTemporaryQueueEvent []any
 
-func publishAsyncEvent(schema *entitySchema, event asyncTemporaryQueueEvent) {
-	schema.asyncTemporaryQueue.Enqueue(event)
+type AsyncBufferMode int
+
+const (
+	AsyncBufferQueue AsyncBufferMode = iota
+	AsyncBufferSync
+	AsyncBufferBlockingTimeout
+	AsyncBufferWAL
+)
+
+type AsyncBufferOptions struct {
+	Mode         AsyncBufferMode
+	QueueSize    int
+	Timeout      time.Duration
+	WALDirectory string
+	DrainTimeout time.Duration
+}
+
+func publishAsyncEvent(orm ORM, schema *entitySchema, event asyncTemporaryQueueEvent) {
+	options := orm.Engine().(*engineImplementation).asyncBuffer
+	switch options.Mode {
+	case AsyncBufferSync:
+		pushAsyncEvents(orm, schema, []string{event.marshal()})
+	case AsyncBufferBlockingTimeout:
+		ormImpl := orm.(*ormImplementation)
+		if ormImpl.asyncBufferReserved[schema] > 0 {
+			ormImpl.asyncBufferReserved[schema]--
+		} else {
+			schema.asyncBufferReserved.Add(1)
+		}
+		schema.asyncTemporaryQueue.Enqueue(event)
+	case AsyncBufferWAL:
+		schema.asyncBufferWAL.publish(schema, event)
+	default:
+		schema.asyncTemporaryQueue.Enqueue(event)
+	}
+}
+
+func (orm *ormImplementation) countAsyncEvents(schema *entitySchema, events int64) {
+	if orm.plan != nil || orm.engine.asyncBuffer.Mode != AsyncBufferBlockingTimeout {
+		return
+	}
+	if orm.asyncEventsToReserve == nil {
+		orm.asyncEventsToReserve = make(map[*entitySchema]int64)
+	}
+	orm.asyncEventsToReserve[schema] += events
+}
+
+// reserveAsyncBuffer takes buffer capacity for counted events before any side effect of the flush is applied,
+// so publishing them later never blocks or fails
+func (orm *ormImplementation) reserveAsyncBuffer() (map[*entitySchema]int64, error) {
+	events := orm.asyncEventsToReserve
+	orm.asyncEventsToReserve = nil
+	if len(events) == 0 {
+		return nil, nil
+	}
+	deadline := time.Now().Add(orm.engine.asyncBuffer.Timeout)
+	reserved := make(map[*entitySchema]int64, len(events))
+	for schema, total := range events {
+		if !schema.reserveAsyncBuffer(total, deadline) {
+			for reservedSchema, reservedTotal := range reserved {
+				reservedSchema.asyncBufferReserved.Add(-reservedTotal)
+			}
+			return nil, ErrAsyncBufferFull
+		}
+		reserved[schema] = total
+	}
+	if orm.asyncBufferReserved == nil {
+		orm.asyncBufferReserved = make(map[*entitySchema]int64)
+	}
+	for schema, total := range reserved {
+		orm.asyncBufferReserved[schema] += total
+	}
+	return reserved, nil
+}
+
+func (orm *ormImplementation) releaseAsyncBuffer(reserved map[*entitySchema]int64) {
+	for schema, total := range reserved {
+		held := orm.asyncBufferReserved[schema]
+		total = min(total, held)
+		schema.asyncBufferReserved.Add(-total)
+		if held == total {
+			delete(orm.asyncBufferReserved, schema)
+		} else {
+			orm.asyncBufferReserved[schema] = held - total
+		}
+	}
+}
+
+func (e *entitySchema) reserveAsyncBuffer(events int64, deadline time.Time) bool {
+	for {
+		reserved := e.asyncBufferReserved.Load()
+		if reserved+events <= e.asyncBufferSize {
+			if e.asyncBufferReserved.CompareAndSwap(reserved, reserved+events) {
+				return true
+			}
+			continue
+		}
+		if time.Now().After(deadline) {
+			return false
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func pushAsyncEvents(orm ORM, schema *entitySchema, events []string) {
+	r := orm.Engine().Redis(schema.getForcedRedisCode())
+	engine := orm.Engine().(*engineImplementation)
+	p := orm.RedisPipeLine(r.GetCode())
+	if engine.asyncFlushStreams != nil {
+		for _, event := range events {
+			partition := engine.asyncStreamPartition(asyncJSONEventEntityID(event))
+			p.XAdd(asyncStreamName(schema.asyncCacheKey, partition), []string{asyncStreamsEventField, event})
+		}
+	} else {
+		values := make([]any, len(events))
+		for i, event := range events {
+			values[i] = event
+		}
+		p.RPush(schema.asyncCacheKey, values...)
+		p.RPush(schema.asyncCacheKey+asyncTimestampsSuffix, asyncTimestamp(len(events)))
+	}
+	p.Exec(orm)
 }
 
 func ConsumeAsyncBuffer(orm ORM, errF func(err error)) (stop func()) {
 	engine := orm.Engine().(*engineImplementation)
-	if engine.asyncTemporaryIsQueueRunning {
+	if !engine.asyncTemporaryIsQueueRunning.CompareAndSwap(false, true) {
 		panic("consumer is already running")
 	}
-	engine.asyncTemporaryIsQueueRunning = true
 	schemas := orm.Engine().Registry().Entities()
+	stopping := make(chan struct{})
+	finished := make(chan struct{})
+	stopOnce := sync.Once{}
 	stop = func() {
-		if !engine.asyncTemporaryIsQueueRunning {
-			return
-		}
-		for _, schema := range schemas {
-			schema.(*entitySchema).asyncTemporaryQueue.TryEnqueue(nil)
-		}
-		maxIterations := 10000
-		for {
-			maxIterations--
-			if maxIterations == 0 {
-				return
-			}
-			if engine.asyncTemporaryIsQueueRunning {
-				time.Sleep(time.Millisecond)
-				continue
-			}
-			return
+		stopOnce.Do(func() {
+			close(stopping)
+		})
+		select {
+		case <-finished:
+		case <-time.After(engine.asyncBuffer.DrainTimeout):
 		}
 	}
 	go func() {
@@ -50,20 +165,23 @@ func ConsumeAsyncBuffer(orm ORM, errF func(err error)) (stop func()) {
 			waitGroup.Add(1)
 			go func() {
 				defer waitGroup.Done()
-				consumeAsyncTempEvent(orm.Clone(), schemaLocal, errF)
+				consumeAsyncTempEvent(orm.Clone(), schemaLocal, stopping, errF)
 			}()
 		}
 		waitGroup.Wait()
-		engine.asyncTemporaryIsQueueRunning = false
+		engine.asyncTemporaryIsQueueRunning.Store(false)
+		close(finished)
 	}()
 	return stop
 }
 
-func consumeAsyncTempEvent(orm ORM, schema *entitySchema, errF func(err error)) {
-	r := orm.Engine().Redis(schema.getForcedRedisCode())
-	buffer := make([]any, redisRPushPackSize)
-	var values []any
+func consumeAsyncTempEvent(orm ORM, schema *entitySchema, stopping <-chan struct{}, errF func(err error)) {
+	buffer := make([]string, redisRPushPackSize)
+	reserved := orm.Engine().(*engineImplementation).asyncBuffer.Mode == AsyncBufferBlockingTimeout
+	var values asyncTemporaryQueueEvent
+	var pending asyncTemporaryQueueEvent
 	var ok bool
+	draining := false
 	for {
 		res := func() bool {
 			defer func() {
@@ -73,40 +191,61 @@ func consumeAsyncTempEvent(orm ORM, schema *entitySchema, errF func(err error))
 						asError = fmt.Errorf("%v", rec)
 					}
 					errF(asError)
-					time.Sleep(time.Second * 3)
+					select {
+					case <-stopping:
+						draining = true
+					case <-time.After(time.Second * 3):
+					}
 				}
 			}()
-			for {
+			for pending == nil {
 				values, ok = schema.asyncTemporaryQueue.TryDequeue()
 				if !ok {
-					time.Sleep(time.Millisecond * 200)
+					if schema.asyncBufferWAL != nil {
+						schema.asyncBufferWAL.replay(orm, schema)
+					}
+					if draining {
+						return false
+					}
+					select {
+					case <-stopping:
+						draining = true
+					case <-time.After(time.Millisecond * 200):
+					}
 					continue
 				}
+				if reserved {
+					schema.asyncBufferReserved.Add(-1)
+				}
 				break
 			}
-
-			if values == nil {
-				return false
+			if pending != nil {
+				values = pending
+				pending = nil
 			}
 			rows := 1
-			asJSON, _ := jsoniter.ConfigFastest.MarshalToString(values)
+			asJSON := values.marshal()
 			buffer[0] = asJSON
-			breakMe := false
-			for i := 1; i < redisRPushPackSize; i++ {
+			size := len(asJSON)
+			for rows < redisRPushPackSize {
 				e, has := schema.asyncTemporaryQueue.TryDequeue()
 				if !has {
 					break
 				}
-				if e == nil {
-					breakMe = true
+				if reserved {
+					schema.asyncBufferReserved.Add(-1)
+				}
+				asJSON = e.marshal()
+				if size+len(asJSON) > redisRPushPackMaxBytes {
+					pending = e
 					break
 				}
-				asJSON, _ = jsoniter.ConfigFastest.MarshalToString(e)
-				buffer[i] = asJSON
+				size += len(asJSON)
+				buffer[rows] = asJSON
 				rows++
 			}
-			r.RPush(orm, schema.asyncCacheKey, buffer[0:rows]...)
-			return !breakMe
+			pushAsyncEvents(orm, schema, buffer[0:rows])
+			return true
 		}()
 		if !res {
 			return
@@ -0,0 +1,136 @@
+package beeorm
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type flushAsyncBufferEntity struct {
+	ID   uint64
+	Name string `orm:"required"`
+}
+
+func TestAsyncBufferDrain(t *testing.T) {
+	var entity *flushAsyncBufferEntity
+	orm := PrepareTables(t, NewRegistry(), entity)
+	schema := getEntitySchema[flushAsyncBufferEntity](orm)
+	r := orm.Engine().Redis(DefaultPoolCode)
+
+	stop := ConsumeAsyncBuffer(orm, func(err error) {
+		panic(err)
+	})
+	for i := 0; i < 100; i++ {
+		entity = NewEntity[flushAsyncBufferEntity](orm)
+		entity.Name = "name " + strconv.Itoa(i)
+		assert.NoError(t, orm.FlushAsync())
+	}
+	stop()
+	stop()
+	assert.Equal(t, int64(100), r.LLen(orm, schema.asyncCacheKey))
+	assert.False(t, orm.Engine().(*engineImplementation).asyncTemporaryIsQueueRunning.Load())
+}
+
+func TestAsyncBufferSync(t *testing.T) {
+	registry := NewRegistry()
+	registry.SetAsyncBuffer(&AsyncBufferOptions{Mode: AsyncBufferSync})
+	var entity *flushAsyncBufferEntity
+	orm := PrepareTables(t, registry, entity)
+	schema := getEntitySchema[flushAsyncBufferEntity](orm)
+	r := orm.Engine().Redis(DefaultPoolCode)
+
+	entity = NewEntity[flushAsyncBufferEntity](orm)
+	entity.Name = "a"
+	assert.NoError(t, orm.FlushAsync())
+	assert.Equal(t, int64(1), r.LLen(orm, schema.asyncCacheKey))
+	_, queued := schema.asyncTemporaryQueue.TryDequeue()
+	assert.False(t, queued)
+	assert.NoError(t, ConsumeAsyncFlushEvents(orm, false))
+	entity, found := GetByID[flushAsyncBufferEntity](orm, entity.ID)
+	assert.True(t, found)
+	assert.Equal(t, "a", entity.Name)
+}
+
+func TestAsyncBufferBlockingTimeout(t *testing.T) {
+	registry := NewRegistry()
+	registry.SetAsyncBuffer(&AsyncBufferOptions{Mode: AsyncBufferBlockingTimeout, QueueSize: 2, Timeout: time.Millisecond * 10})
+	var entity *flushAsyncBufferEntity
+	orm := PrepareTables(t, registry, entity)
+
+	for i := 0; i < 2; i++ {
+		entity = NewEntity[flushAsyncBufferEntity](orm)
+		entity.Name = "name " + strconv.Itoa(i)
+		assert.NoError(t, orm.FlushAsync())
+	}
+	schema := getEntitySchema[flushAsyncBufferEntity](orm)
+	r := orm.Engine().Redis(DefaultPoolCode)
+	entity = NewEntity[flushAsyncBufferEntity](orm)
+	entity.Name = "name 3"
+	assert.ErrorIs(t, orm.FlushAsync(), ErrAsyncBufferFull)
+	assert.Equal(t, int64(2), schema.asyncBufferReserved.Load())
+	orm.ClearFlush()
+
+	stop := ConsumeAsyncBuffer(orm, func(err error) {
+		panic(err)
+	})
+	stop()
+	assert.Equal(t, int64(0), schema.asyncBufferReserved.Load())
+	assert.Equal(t, int64(2), r.LLen(orm, schema.asyncCacheKey))
+	entity = NewEntity[flushAsyncBufferEntity](orm)
+	entity.Name = "name 4"
+	assert.NoError(t, orm.FlushAsync())
+	assert.Equal(t, int64(1), schema.asyncBufferReserved.Load())
+
+	registry = NewRegistry()
+	registry.SetAsyncBuffer(&AsyncBufferOptions{Mode: AsyncBufferBlockingTimeout})
+	registry.RegisterMySQL("root:root@tcp(localhost:3377)/test", DefaultPoolCode, &MySQLOptions{})
+	_, err := registry.Validate()
+	assert.EqualError(t, err, "async buffer timeout must be greater than zero")
+}
+
+func TestAsyncBufferWAL(t *testing.T) {
+	directory := t.TempDir()
+	registry := NewRegistry()
+	registry.SetAsyncBuffer(&AsyncBufferOptions{Mode: AsyncBufferWAL, QueueSize: 2, WALDirectory: directory})
+	var entity *flushAsyncBufferEntity
+	orm := PrepareTables(t, registry, entity)
+	schema := getEntitySchema[flushAsyncBufferEntity](orm)
+	r := orm.Engine().Redis(DefaultPoolCode)
+	walFile := filepath.Join(directory, "default_flushAsyncBufferEntity.wal")
+
+	for i := 0; i < 5; i++ {
+		entity = NewEntity[flushAsyncBufferEntity](orm)
+		entity.Name = "name " + strconv.Itoa(i)
+		assert.NoError(t, orm.FlushAsync())
+	}
+	assert.FileExists(t, walFile)
+
+	restarted := newAsyncBufferWAL(directory, schema)
+	assert.True(t, restarted.spilled)
+	assert.True(t, restarted.rotate())
+	assert.NoFileExists(t, walFile)
+	content, err := os.ReadFile(walFile + ".replay")
+	assert.NoError(t, err)
+	replayed := strconv.Itoa(strings.Index(string(content), "\n") + 1)
+	assert.NoError(t, os.WriteFile(walFile+".offset", []byte(replayed), 0600))
+	restarted.replay(orm, schema)
+	assert.NoFileExists(t, walFile+".replay")
+	assert.NoFileExists(t, walFile+".offset")
+	assert.False(t, restarted.spilled)
+	assert.Equal(t, int64(2), r.LLen(orm, schema.asyncCacheKey))
+	events := ReadAsyncFlushEvents(orm)[0].Events(10)
+	assert.Contains(t, events[0].QueryAttributes, "name 3")
+	assert.Contains(t, events[1].QueryAttributes, "name 4")
+
+	stop := ConsumeAsyncBuffer(orm, func(err error) {
+		panic(err)
+	})
+	stop()
+	assert.NoFileExists(t, walFile)
+	assert.Equal(t, int64(4), r.LLen(orm, schema.asyncCacheKey))
+}
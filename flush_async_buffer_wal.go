@@ -0,0 +1,130 @@
+package beeorm
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+type asyncBufferWAL struct {
+	mutex   sync.Mutex
+	path    string
+	file    *os.File
+	spilled bool
+}
+
+func newAsyncBufferWAL(directory string, schema *entitySchema) *asyncBufferWAL {
+	name := strings.NewReplacer(":", "_", "/", "_").Replace(schema.mysqlPoolCode + "_" + schema.tableName)
+	wal := &asyncBufferWAL{path: filepath.Join(directory, name+".wal")}
+	wal.spilled = fileExists(wal.path) || fileExists(wal.replayPath())
+	return wal
+}
+
+func (w *asyncBufferWAL) replayPath() string {
+	return w.path + ".replay"
+}
+
+func (w *asyncBufferWAL) offsetPath() string {
+	return w.path + ".offset"
+}
+
+func (w *asyncBufferWAL) publish(schema *entitySchema, event asyncTemporaryQueueEvent) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	if !w.spilled && schema.asyncTemporaryQueue.TryEnqueue(event) {
+		return
+	}
+	if w.file == nil {
+		file, err := os.OpenFile(w.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+		checkError(err)
+		w.file = file
+	}
+	_, err := w.file.WriteString(event.marshal() + "\n")
+	checkError(err)
+	checkError(w.file.Sync())
+	w.spilled = true
+}
+
+func (w *asyncBufferWAL) replay(orm ORM, schema *entitySchema) {
+	for w.rotate() {
+		w.replayFile(orm, schema)
+	}
+}
+
+func (w *asyncBufferWAL) rotate() bool {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	if !w.spilled {
+		return false
+	}
+	if fileExists(w.replayPath()) {
+		return true
+	}
+	if w.file != nil {
+		checkError(w.file.Close())
+		w.file = nil
+	}
+	if !fileExists(w.path) {
+		w.spilled = false
+		return false
+	}
+	checkError(os.Rename(w.path, w.replayPath()))
+	return true
+}
+
+func (w *asyncBufferWAL) replayFile(orm ORM, schema *entitySchema) {
+	offset := int64(0)
+	saved, err := os.ReadFile(w.offsetPath())
+	if err == nil {
+		offset, err = strconv.ParseInt(string(saved), 10, 64)
+	}
+	if err != nil && !os.IsNotExist(err) {
+		panic(err)
+	}
+	file, err := os.Open(w.replayPath())
+	checkError(err)
+	defer file.Close()
+	_, err = file.Seek(offset, io.SeekStart)
+	checkError(err)
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), redisRPushPackMaxBytes)
+	events := make([]string, 0, redisRPushPackSize)
+	size := 0
+	push := func() {
+		pushAsyncEvents(orm, schema, events)
+		checkError(os.WriteFile(w.offsetPath(), []byte(strconv.FormatInt(offset, 10)), 0600))
+		events = events[0:0]
+		size = 0
+	}
+	for scanner.Scan() {
+		line := scanner.Text()
+		if len(events) == redisRPushPackSize || size+len(line) > redisRPushPackMaxBytes {
+			push()
+		}
+		offset += int64(len(line)) + 1
+		if line == "" {
+			continue
+		}
+		events = append(events, line)
+		size += len(line)
+	}
+	checkError(scanner.Err())
+	if len(events) > 0 {
+		push()
+	}
+	checkError(file.Close())
+	checkError(os.Remove(w.replayPath()))
+	err = os.Remove(w.offsetPath())
+	if err != nil && !os.IsNotExist(err) {
+		panic(err)
+	}
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
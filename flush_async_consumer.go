@@ -3,7 +3,6 @@ package beeorm
 import (
 	"context"
 	"fmt"
-	"slices"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -38,6 +37,9 @@ var mySQLErrorCodesToSkip = []uint16{
 const asyncConsumerLockName = "async_consumer"
 
 func ConsumeAsyncFlushEvents(orm ORM, block bool) error {
+	if orm.Engine().(*engineImplementation).asyncFlushStreams != nil {
+		return consumeAsyncFlushStreams(orm, block)
+	}
 	lock, lockObtained := orm.Engine().Redis(DefaultPoolCode).GetLocker().Obtain(orm, asyncConsumerLockName, time.Minute, 0)
 	if !lockObtained {
 		return redislock.ErrNotObtained
@@ -116,7 +118,9 @@ func consumeAsyncEvents(context context.Context, orm ORM, list string, db DB, r
 
 		values = r.LRange(orm, list, 0, asyncConsumerPage-1)
 		if len(values) > 0 {
-			handleAsyncEvents(context, orm, list, db, r, values)
+			handleAsyncEvents(context, orm, list, db, r, values, func(from, to int) {
+				trimAsyncList(orm, r, list, to-from)
+			})
 		}
 		if len(values) < asyncConsumerPage {
 			if !block || context.Err() != nil {
@@ -127,7 +131,7 @@ func consumeAsyncEvents(context context.Context, orm ORM, list string, db DB, r
 	}
 }
 
-func handleAsyncEvents(context context.Context, orm ORM, list string, db DB, r RedisCache, values []string) {
+func handleAsyncEvents(context context.Context, orm ORM, list string, db DB, r RedisCache, values []string, ack func(from, to int)) {
 	operations := len(values)
 	inTX := operations > 1
 	func() {
@@ -143,41 +147,55 @@ func handleAsyncEvents(context context.Context, orm ORM, list string, db DB, r R
 		} else {
 			d = dbPool
 		}
+		var flushed []*flushedEvent
+		var incremented []*asyncEvent
 		for _, event := range values {
 			if context.Err() != nil {
 				return
 			}
-			err := handleAsyncEvent(orm, d, event)
+			events, increments, err := handleAsyncEvent(orm, d, event)
 			if err != nil {
 				if inTX {
 					d.(DBTransaction).Rollback(orm)
 				}
-				handleAsyncEventsOneByOne(context, orm, list, db, r, values)
+				r.HIncrBy(orm, list+asyncStatsSuffix, asyncStatsOneByOne, 1)
+				handleAsyncEventsOneByOne(context, orm, list, db, r, values, ack)
 				return
 			}
+			flushed = append(flushed, events...)
+			if increments != nil {
+				incremented = append(incremented, increments)
+			}
 		}
 		if inTX {
 			d.(DBTransaction).Commit(orm)
 		}
-		r.Ltrim(orm, list, int64(len(values)), -1)
+		refreshAsyncIncrements(orm, incremented)
+		ack(0, len(values))
+		recordAsyncStats(orm, r, list, len(values), 1, nil)
+		orm.(*ormImplementation).dispatchFlushedEvents(flushed...)
 	}()
 }
 
-func handleAsyncEvent(orm ORM, db DBBase, value string) (err *mysql.MySQLError) {
+func handleAsyncEvent(orm ORM, db DBBase, value string) (flushed []*flushedEvent, incremented *asyncEvent, err error) {
 	defer func() {
 		if rec := recover(); rec != nil {
 			asMySQLError, isMySQLError := rec.(*mysql.MySQLError)
-			if isMySQLError && slices.Contains(mySQLErrorCodesToSkip, asMySQLError.Number) {
+			if isMySQLError {
 				err = asMySQLError
 				return
 			}
 			panic(rec)
 		}
 	}()
+	if isStructuredAsyncEvent(value) {
+		return handleStructuredAsyncEvent(orm, db, value)
+	}
+	flushedEvents, value := splitAsyncEvent(value)
 	var data []any
 	_ = jsoniter.ConfigFastest.UnmarshalFromString(value, &data)
 	if len(data) == 0 {
-		return nil
+		return nil, nil, nil
 	}
 	sql, valid := data[0].(string)
 	if !valid {
@@ -185,21 +203,61 @@ func handleAsyncEvent(orm ORM, db DBBase, value string) (err *mysql.MySQLError)
 	}
 	if len(data) == 1 {
 		db.Exec(orm, sql)
-		return nil
+		return decodeAsyncFlushedEvents(orm, flushedEvents), nil, nil
 	}
 	db.Exec(orm, sql, data[1:]...)
-	return nil
+	return decodeAsyncFlushedEvents(orm, flushedEvents), nil, nil
 }
 
-func handleAsyncEventsOneByOne(context context.Context, orm ORM, list string, db DB, r RedisCache, values []string) {
-	for _, event := range values {
+func handleAsyncEventsOneByOne(context context.Context, orm ORM, list string, db DB, r RedisCache, values []string, ack func(from, to int)) {
+	applied := 0
+	skipped := make(map[uint16]int)
+	defer func() {
+		recordAsyncStats(orm, r, list, applied, 0, skipped)
+	}()
+	for i, event := range values {
 		if context.Err() != nil {
 			return
 		}
-		err := handleAsyncEvent(orm, db, event)
+		flushed, incremented, err := handleAsyncEvent(orm, db, event)
 		if err != nil {
+			policy := getAsyncErrorPolicy(orm, db, event)
+			for attempt := 1; err != nil; attempt++ {
+				if policy.OnAsyncError != nil {
+					policy.OnAsyncError(decodeFlushEvent(orm, event), err)
+				}
+				action := policy.action(err)
+				if action == AsyncErrorStop {
+					panic(err)
+				}
+				if action != AsyncErrorRetry || attempt >= policy.retryPolicy().MaxAttempts || context.Err() != nil {
+					break
+				}
+				select {
+				case <-context.Done():
+					return
+				case <-time.After(policy.retryPolicy().delay(attempt)):
+				}
+				flushed, incremented, err = handleAsyncEvent(orm, db, event)
+			}
+		}
+		if err == nil {
+			applied++
+			if incremented != nil {
+				refreshAsyncIncrements(orm, []*asyncEvent{incremented})
+			}
+		} else {
 			r.RPush(orm, list+flushAsyncEventsListErrorSuffix, event, err.Error())
+			asMySQLError, isMySQLError := err.(*mysql.MySQLError)
+			if isMySQLError {
+				skipped[asMySQLError.Number]++
+			}
+			conflict, isConflict := err.(*VersionConflictError)
+			if isConflict {
+				invalidateEntityCache(orm, conflict.Schema.(*entitySchema), conflict.ID)
+			}
 		}
-		r.Ltrim(orm, list, 1, -1)
+		ack(i, i+1)
+		orm.(*ormImplementation).dispatchFlushedEvents(flushed...)
 	}
 }
@@ -173,7 +173,7 @@ func TestAsyncConsumer(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Equal(t, int64(0), orm.Engine().Redis(DefaultPoolCode).LLen(orm, schema.asyncCacheKey))
 	assert.Equal(t, int64(2), orm.Engine().Redis(DefaultPoolCode).LLen(orm, schema.asyncCacheKey+flushAsyncEventsListErrorSuffix))
-	assert.Contains(t, orm.Engine().Redis(DefaultPoolCode).LPop(orm, schema.asyncCacheKey+flushAsyncEventsListErrorSuffix), "INSERT INTO `flushEntity`")
+	assert.Contains(t, decodeFlushEvent(orm, orm.Engine().Redis(DefaultPoolCode).LPop(orm, schema.asyncCacheKey+flushAsyncEventsListErrorSuffix)).SQL, "INSERT INTO `flushEntity`")
 	assert.Equal(t, "Error 1062 (23000): Duplicate entry 'Valid name 2' for key 'flushEntity.name'", orm.Engine().Redis(DefaultPoolCode).LPop(orm, schema.asyncCacheKey+flushAsyncEventsListErrorSuffix))
 }
 
@@ -184,3 +184,69 @@ func runAsyncConsumer(orm ORM, block bool) error {
 	stop()
 	return ConsumeAsyncFlushEvents(orm, block)
 }
+
+func TestAsyncConsumerErrorPolicy(t *testing.T) {
+	registry := NewRegistry()
+	var engineErrors []FlushEvent
+	registry.SetAsyncErrorPolicy(&AsyncErrorPolicy{OnAsyncError: func(event FlushEvent, _ error) {
+		engineErrors = append(engineErrors, event)
+	}})
+	orm := PrepareTables(t, registry, flushEntityAsync{}, flushEntityAsync3{})
+	schema := getEntitySchema[flushEntityAsync](orm)
+	schema3 := getEntitySchema[flushEntityAsync3](orm)
+	r := orm.Engine().Redis(DefaultPoolCode)
+
+	var schemaErrors []error
+	schema3.SetAsyncErrorPolicy(&AsyncErrorPolicy{
+		RetryCodes: []uint16{1054},
+		Retry:      &FlushRetryPolicy{MaxAttempts: 3, Backoff: time.Millisecond},
+		OnAsyncError: func(_ FlushEvent, err error) {
+			schemaErrors = append(schemaErrors, err)
+		},
+	})
+	entity3 := NewEntity[flushEntityAsync3](orm)
+	entity3.Name = "a"
+	assert.NoError(t, orm.FlushAsync())
+	schema3.GetDB().Exec(orm, "ALTER TABLE flushEntityAsync3 DROP COLUMN Name")
+	assert.NoError(t, runAsyncConsumer(orm, false))
+	assert.Len(t, schemaErrors, 3)
+	assert.Len(t, engineErrors, 0)
+	assert.Equal(t, int64(0), r.LLen(orm, schema3.asyncCacheKey))
+	assert.Equal(t, int64(2), r.LLen(orm, schema3.asyncCacheKey+flushAsyncEventsListErrorSuffix))
+
+	schemaErrors = nil
+	schema3.SetAsyncErrorPolicy(&AsyncErrorPolicy{SkipCodes: []uint16{1054}})
+	entity3 = NewEntity[flushEntityAsync3](orm)
+	entity3.Name = "b"
+	assert.NoError(t, orm.FlushAsync())
+	assert.NoError(t, runAsyncConsumer(orm, false))
+	assert.Len(t, schemaErrors, 0)
+	assert.Equal(t, int64(4), r.LLen(orm, schema3.asyncCacheKey+flushAsyncEventsListErrorSuffix))
+
+	entity := NewEntity[flushEntityAsync](orm)
+	entity.Name = "c"
+	assert.NoError(t, orm.FlushAsync())
+	schema.GetDB().Exec(orm, "ALTER TABLE flushEntityAsync DROP COLUMN Name")
+	err := runAsyncConsumer(orm, false)
+	assert.EqualError(t, err, "Error 1054 (42S22): Unknown column 'Name' in 'field list'")
+	assert.Len(t, engineErrors, 1)
+	assert.Contains(t, engineErrors[0].SQL, "INSERT INTO `flushEntityAsync`")
+	assert.Equal(t, int64(1), r.LLen(orm, schema.asyncCacheKey))
+	assert.Equal(t, int64(0), r.LLen(orm, schema.asyncCacheKey+flushAsyncEventsListErrorSuffix))
+
+	r.Del(orm, schema.asyncCacheKey, schema.asyncCacheKey+asyncTimestampsSuffix)
+	schema3.SetAsyncErrorPolicy(&AsyncErrorPolicy{RetryCodes: []uint16{1054}, Retry: &FlushRetryPolicy{MaxAttempts: 3, Backoff: time.Hour}})
+	entity3 = NewEntity[flushEntityAsync3](orm)
+	entity3.Name = "d"
+	assert.NoError(t, orm.FlushAsync())
+	ConsumeAsyncBuffer(orm, func(err error) {
+		panic(err)
+	})()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*100)
+	defer cancel()
+	start := time.Now()
+	assert.NoError(t, ConsumeAsyncFlushEvents(orm.CloneWithContext(ctx), false))
+	assert.Less(t, time.Since(start), time.Second*5)
+	assert.Equal(t, int64(1), r.LLen(orm, schema3.asyncCacheKey))
+	assert.Equal(t, int64(4), r.LLen(orm, schema3.asyncCacheKey+flushAsyncEventsListErrorSuffix))
+}
@@ -0,0 +1,100 @@
+package beeorm
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+type AsyncErrorAction int
+
+const (
+	AsyncErrorStop AsyncErrorAction = iota
+	AsyncErrorSkip
+	AsyncErrorRetry
+)
+
+var defaultAsyncErrorRetryPolicy = &FlushRetryPolicy{MaxAttempts: 5, Backoff: time.Millisecond * 100, MaxBackoff: time.Second * 5}
+
+var defaultAsyncErrorPolicy = &AsyncErrorPolicy{
+	SkipCodes:  mySQLErrorCodesToSkip,
+	RetryCodes: mySQLErrorCodesToRetry,
+}
+
+type AsyncErrorPolicy struct {
+	SkipCodes     []uint16
+	RetryCodes    []uint16
+	DefaultAction AsyncErrorAction
+	Retry         *FlushRetryPolicy
+	OnAsyncError  func(event FlushEvent, err error)
+}
+
+func (p *AsyncErrorPolicy) action(err error) AsyncErrorAction {
+	var versionConflict *VersionConflictError
+	var eventError *asyncEventError
+	if errors.As(err, &versionConflict) || errors.As(err, &eventError) {
+		return AsyncErrorSkip
+	}
+	var mySQLError *mysql.MySQLError
+	if !errors.As(err, &mySQLError) {
+		return AsyncErrorStop
+	}
+	if slices.Contains(p.SkipCodes, mySQLError.Number) {
+		return AsyncErrorSkip
+	}
+	if slices.Contains(p.RetryCodes, mySQLError.Number) {
+		return AsyncErrorRetry
+	}
+	return p.DefaultAction
+}
+
+func (p *AsyncErrorPolicy) retryPolicy() *FlushRetryPolicy {
+	if p.Retry != nil {
+		return p.Retry
+	}
+	return defaultAsyncErrorRetryPolicy
+}
+
+func (e *entitySchema) SetAsyncErrorPolicy(policy *AsyncErrorPolicy) {
+	e.asyncErrorPolicy.Store(policy)
+}
+
+func getAsyncErrorPolicy(orm ORM, db DB, event string) *AsyncErrorPolicy {
+	schema := asyncEventSchema(orm, db, event)
+	if schema != nil {
+		if policy := schema.asyncErrorPolicy.Load(); policy != nil {
+			return policy
+		}
+	}
+	policy := orm.Engine().(*engineImplementation).asyncErrorPolicy
+	if policy != nil {
+		return policy
+	}
+	return defaultAsyncErrorPolicy
+}
+
+func asyncEventSchema(orm ORM, db DB, event string) *entitySchema {
+	if isStructuredAsyncEvent(event) {
+		_, schema, _ := decodeAsyncEvent(orm, event)
+		return schema
+	}
+	sql := decodeFlushEvent(orm, event).SQL
+	start := strings.Index(sql, "`")
+	if start < 0 {
+		return nil
+	}
+	end := strings.Index(sql[start+1:], "`")
+	if end < 0 {
+		return nil
+	}
+	table := sql[start+1 : start+1+end]
+	for _, schema := range orm.Engine().Registry().Entities() {
+		if schema.GetTableName() == table && schema.GetDB() == db {
+			return schema.(*entitySchema)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,218 @@
+package beeorm
+
+import (
+	"fmt"
+	"maps"
+	"strconv"
+	"strings"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+const asyncEventVersion = 2
+
+type asyncEvent struct {
+	Version    int       `json:"v"`
+	Schema     string    `json:"s"`
+	Type       FlushType `json:"t"`
+	ID         string    `json:"i"`
+	Before     Bind      `json:"b,omitempty"`
+	After      Bind      `json:"a,omitempty"`
+	Meta       Meta      `json:"m,omitempty"`
+	Increments Bind      `json:"inc,omitempty"`
+	Force      bool      `json:"f,omitempty"`
+	Restore    bool      `json:"r,omitempty"`
+	SQL        string    `json:"q,omitempty"`
+	Params     []any     `json:"p,omitempty"`
+}
+
+type asyncEventError struct {
+	message string
+}
+
+func (e *asyncEventError) Error() string {
+	return e.message
+}
+
+func (orm *ormImplementation) newAsyncEvent(schema *entitySchema, flushType FlushType, id uint64, before, after Bind) *asyncEvent {
+	return &asyncEvent{
+		Version: asyncEventVersion,
+		Schema:  schema.t.String(),
+		Type:    flushType,
+		ID:      strconv.FormatUint(id, 10),
+		Before:  encodeAsyncBind(before),
+		After:   encodeAsyncBind(after),
+		Meta:    maps.Clone(orm.meta),
+	}
+}
+
+func (orm *ormImplementation) newLogTableEvent(schema, logTableSchema *entitySchema, id uint64, before, after Bind) asyncTemporaryQueueEvent {
+	bind := Bind{"EntityID": id, "Date": time.Now().Format(time.DateTime)}
+	if len(orm.meta) > 0 {
+		bind["Meta"], _ = jsoniter.ConfigFastest.MarshalToString(orm.meta)
+	}
+	if before != nil {
+		bind["Before"], _ = jsoniter.ConfigFastest.MarshalToString(before)
+	}
+	if after != nil {
+		bind["After"], _ = jsoniter.ConfigFastest.MarshalToString(after)
+	}
+	event := orm.newAsyncEvent(logTableSchema, Insert, logTableSchema.uuid(orm), nil, bind)
+	event.Schema = "beeorm.LogEntity[" + schema.t.String() + "]"
+	return asyncTemporaryQueueEvent{event}
+}
+
+func (e asyncTemporaryQueueEvent) structured() (*asyncEvent, bool) {
+	if len(e) != 1 {
+		return nil, false
+	}
+	structured, isStructured := e[0].(*asyncEvent)
+	return structured, isStructured
+}
+
+func (e asyncTemporaryQueueEvent) marshal() string {
+	if structured, isStructured := e.structured(); isStructured {
+		asJSON, _ := jsoniter.ConfigFastest.MarshalToString(structured)
+		return asJSON
+	}
+	asJSON, _ := jsoniter.ConfigFastest.MarshalToString(e)
+	return asJSON
+}
+
+func isStructuredAsyncEvent(value string) bool {
+	return strings.HasPrefix(value, "{")
+}
+
+func decodeAsyncEvent(orm ORM, value string) (*asyncEvent, *entitySchema, error) {
+	event := &asyncEvent{}
+	err := jsoniter.ConfigFastest.UnmarshalFromString(value, event)
+	if err != nil {
+		return nil, nil, &asyncEventError{message: "invalid async event: " + err.Error()}
+	}
+	if event.Version > asyncEventVersion {
+		return nil, nil, &asyncEventError{message: fmt.Sprintf("unsupported async event version %d", event.Version)}
+	}
+	schema := orm.Engine().Registry().EntitySchema(event.Schema)
+	if schema == nil {
+		return nil, nil, &asyncEventError{message: fmt.Sprintf("entity '%s' is not registered", event.Schema)}
+	}
+	return event, schema.(*entitySchema), nil
+}
+
+func (e *asyncEvent) buildSQL(schema *entitySchema) (sql string, params []any) {
+	if e.SQL != "" {
+		return e.SQL, e.Params
+	}
+	table := "`" + schema.GetTableName() + "`"
+	switch {
+	case e.Type == Insert && e.Restore:
+		return "UPDATE " + table + " SET `" + fakeDeleteColumn + "` = 0 WHERE ID = ?", []any{e.ID}
+	case e.Type == Insert:
+		sql = "INSERT INTO " + table + "(`ID`"
+		params = []any{e.ID}
+		for _, column := range schema.columnNames[1:] {
+			value, has := e.After[column]
+			if has {
+				sql += ",`" + column + "`"
+				params = append(params, value)
+			}
+		}
+		return sql + ") VALUES(?" + strings.Repeat(",?", len(params)-1) + ")", params
+	case e.Type == Update:
+		for _, column := range schema.columnNames[1:] {
+			value, has := e.After[column]
+			if !has {
+				continue
+			}
+			if len(params) > 0 {
+				sql += ","
+			}
+			delta, isIncrement := e.Increments[column]
+			if isIncrement {
+				sql += "`" + column + "`=`" + column + "`+?"
+				value = delta
+			} else {
+				sql += "`" + column + "`=?"
+			}
+			params = append(params, value)
+		}
+		if len(params) == 0 {
+			return "", nil
+		}
+		sql = "UPDATE " + table + " SET " + sql + " WHERE ID = ?"
+		params = append(params, e.ID)
+		if schema.versionColumn != "" {
+			version, has := e.Before[schema.versionColumn]
+			if has {
+				sql += " AND `" + schema.versionColumn + "` = ?"
+				params = append(params, version)
+			}
+		}
+		return sql, params
+	default:
+		if schema.softDelete && !e.Force {
+			return "UPDATE " + table + " SET `" + fakeDeleteColumn + "` = `ID` WHERE ID = ?", []any{e.ID}
+		}
+		return "DELETE FROM " + table + " WHERE ID = ?", []any{e.ID}
+	}
+}
+
+func (e *asyncEvent) flushedEvent(schema *entitySchema) *flushedEvent {
+	id, _ := strconv.ParseUint(e.ID, 10, 64)
+	return &flushedEvent{
+		schema:    schema,
+		flushType: e.Type,
+		id:        id,
+		before:    decodeAsyncBind(schema, e.Before),
+		after:     decodeAsyncBind(schema, e.After),
+		meta:      e.Meta,
+	}
+}
+
+func handleStructuredAsyncEvent(orm ORM, db DBBase, value string) ([]*flushedEvent, *asyncEvent, error) {
+	event, schema, err := decodeAsyncEvent(orm, value)
+	if err != nil {
+		return nil, nil, err
+	}
+	sql, params := event.buildSQL(schema)
+	if sql != "" {
+		res := db.Exec(orm, sql, params...)
+		if res.RowsAffected() == 0 && event.Type == Update && event.SQL == "" {
+			conflict := event.versionConflict(schema)
+			if conflict != nil {
+				return nil, nil, conflict
+			}
+		}
+	}
+	var incremented *asyncEvent
+	if len(event.Increments) > 0 && (schema.hasRedisCache || schema.hasLocalCache) {
+		incremented = event
+	}
+	if !schema.hasSubscribers() {
+		return nil, incremented, nil
+	}
+	return []*flushedEvent{event.flushedEvent(schema)}, incremented, nil
+}
+
+func refreshAsyncIncrements(orm ORM, events []*asyncEvent) {
+	for _, event := range events {
+		schema := orm.Engine().Registry().EntitySchema(event.Schema).(*entitySchema)
+		id, _ := strconv.ParseUint(event.ID, 10, 64)
+		if schema.hasRedisCache {
+			columns := make([]string, 0, len(event.Increments))
+			for column := range event.Increments {
+				columns = append(columns, column)
+			}
+			fetched, found := fetchEntityColumns(orm, schema.GetDB(), schema, id, columns)
+			if found {
+				p := newRedisPipeLine(orm.(*ormImplementation), schema.redisCache.GetCode())
+				schema.updateRedisCacheEntity(p, schema.getCacheKey()+":"+event.ID, fetched)
+				p.Exec(orm)
+			}
+		}
+		if schema.hasLocalCache {
+			schema.localCache.removeEntity(orm, id)
+		}
+	}
+}
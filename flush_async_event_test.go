@@ -0,0 +1,79 @@
+package beeorm
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type flushAsyncEventEntity struct {
+	ID      uint64 `orm:"localCache;redisCache"`
+	Name    string `orm:"required"`
+	Age     uint8
+	Counter uint32
+}
+
+func TestStructuredAsyncEvents(t *testing.T) {
+	var entity *flushAsyncEventEntity
+	orm := PrepareTables(t, NewRegistry(), entity)
+	schema := getEntitySchema[flushAsyncEventEntity](orm)
+	r := orm.Engine().Redis(DefaultPoolCode)
+
+	orm.SetMetaData("source", "test")
+	entity = NewEntity[flushAsyncEventEntity](orm)
+	entity.Name = "a"
+	entity.Age = 10
+	assert.NoError(t, orm.FlushAsync())
+	stop := ConsumeAsyncBuffer(orm, func(err error) {
+		panic(err)
+	})
+	stop()
+	values := r.LRange(orm, schema.asyncCacheKey, 0, -1)
+	assert.Len(t, values, 1)
+	event, eventSchema, err := decodeAsyncEvent(orm, values[0])
+	assert.NoError(t, err)
+	assert.Equal(t, schema, eventSchema)
+	assert.Equal(t, asyncEventVersion, event.Version)
+	assert.Equal(t, "beeorm.flushAsyncEventEntity", event.Schema)
+	assert.Equal(t, Insert, event.Type)
+	assert.Equal(t, strconv.FormatUint(entity.ID, 10), event.ID)
+	assert.Equal(t, "a", event.After["Name"])
+	assert.Nil(t, event.Before)
+	assert.Equal(t, Meta{"source": "test"}, event.Meta)
+	sql, params := event.buildSQL(schema)
+	assert.Equal(t, "INSERT INTO `flushAsyncEventEntity`(`ID`,`Name`,`Age`,`Counter`) VALUES(?,?,?,?)", sql)
+	assert.Len(t, params, 4)
+	assert.NoError(t, ConsumeAsyncFlushEvents(orm, false))
+
+	entity = EditEntity(orm, entity)
+	entity.Name = "b"
+	assert.NoError(t, orm.FlushAsync())
+	assert.NoError(t, IncrementEntityField(orm, entity, "Counter", 3))
+	assert.NoError(t, orm.FlushAsync())
+	assert.NoError(t, runAsyncConsumer(orm, false))
+	schema.DisableCache(true, true)
+	entity, _ = GetByID[flushAsyncEventEntity](orm, entity.ID)
+	assert.Equal(t, "b", entity.Name)
+	assert.Equal(t, uint32(3), entity.Counter)
+
+	r.RPush(orm, schema.asyncCacheKey,
+		`{"v":2,"s":"beeorm.flushAsyncEventEntity","t":1,"i":"`+strconv.FormatUint(entity.ID, 10)+`","a":{"Name":"c","Removed":1}}`,
+		`["UPDATE `+"`flushAsyncEventEntity` SET `Age`=? WHERE ID = ?"+`","11","`+strconv.FormatUint(entity.ID, 10)+`"]`,
+		`{"v":2,"s":"beeorm.unknownEntity","t":0,"i":"1"}`,
+		`{"v":3,"s":"beeorm.flushAsyncEventEntity","t":0,"i":"1"}`)
+	assert.NoError(t, ConsumeAsyncFlushEvents(orm, false))
+	entity, _ = GetByID[flushAsyncEventEntity](orm, entity.ID)
+	assert.Equal(t, "c", entity.Name)
+	assert.Equal(t, uint8(11), entity.Age)
+	errors := ReadAsyncFlushEvents(orm)[0].Errors(10, false)
+	assert.Len(t, errors, 2)
+	assert.Equal(t, "entity 'beeorm.unknownEntity' is not registered", errors[0].Error)
+	assert.Equal(t, "unsupported async event version 3", errors[1].Error)
+
+	DeleteEntity(orm, entity)
+	assert.NoError(t, orm.FlushAsync())
+	assert.NoError(t, runAsyncConsumer(orm, false))
+	_, found := GetByID[flushAsyncEventEntity](orm, entity.ID)
+	assert.False(t, found)
+}
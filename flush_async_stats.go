@@ -0,0 +1,137 @@
+package beeorm
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+const asyncStatsSuffix = ":stats"
+const asyncTimestampsSuffix = ":ts"
+const asyncStatsEvents = "events"
+const asyncStatsBatches = "batches"
+const asyncStatsOneByOne = "one_by_one"
+const asyncStatsSkippedPrefix = "skipped:"
+const asyncStatsRateWindow = 10
+
+type AsyncFlushStats struct {
+	Pending           uint64
+	OldestEventAge    time.Duration
+	EventsPerSecond   float64
+	EventsApplied     uint64
+	BatchesCommitted  uint64
+	OneByOneFallbacks uint64
+	SkippedErrorCodes map[uint16]uint64
+}
+
+const asyncListTrimScript = `
+local n = tonumber(ARGV[1])
+redis.call('LTRIM', KEYS[1], n, -1)
+if redis.call('LLEN', KEYS[1]) == 0 then
+	redis.call('DEL', KEYS[2])
+	return 0
+end
+while n > 0 do
+	local head = redis.call('LINDEX', KEYS[2], 0)
+	if not head then
+		break
+	end
+	local separator = string.find(head, ':', 1, true)
+	local count = tonumber(string.sub(head, 1, separator - 1))
+	if count > n then
+		redis.call('LSET', KEYS[2], 0, (count - n) .. string.sub(head, separator))
+		n = 0
+	else
+		redis.call('LPOP', KEYS[2])
+		n = n - count
+	end
+end
+return 0
+`
+
+func asyncTimestamp(events int) string {
+	return strconv.Itoa(events) + ":" + strconv.FormatInt(time.Now().UnixMilli(), 10)
+}
+
+func trimAsyncList(orm ORM, r RedisCache, list string, total int) {
+	r.Eval(orm, asyncListTrimScript, []string{list, list + asyncTimestampsSuffix}, total)
+}
+
+func asyncStatsRateKey(list string, second int64) string {
+	return list + asyncStatsSuffix + ":" + strconv.FormatInt(second, 10)
+}
+
+func recordAsyncStats(orm ORM, r RedisCache, list string, applied int, batches int, skipped map[uint16]int) {
+	p := orm.RedisPipeLine(r.GetCode())
+	key := list + asyncStatsSuffix
+	if applied > 0 {
+		p.HIncrBy(key, asyncStatsEvents, int64(applied))
+		rateKey := asyncStatsRateKey(list, time.Now().Unix())
+		p.IncrBy(rateKey, int64(applied))
+		p.Expire(rateKey, time.Second*asyncStatsRateWindow*2)
+	}
+	if batches > 0 {
+		p.HIncrBy(key, asyncStatsBatches, int64(batches))
+	}
+	for code, total := range skipped {
+		p.HIncrBy(key, asyncStatsSkippedPrefix+strconv.FormatUint(uint64(code), 10), int64(total))
+	}
+	p.Exec(orm)
+}
+
+func (s *asyncFlushEvents) Stats() *AsyncFlushStats {
+	r := s.orm.Engine().Redis(s.redisPoolName)
+	stats := &AsyncFlushStats{Pending: s.EventsCount(), SkippedErrorCodes: make(map[uint16]uint64)}
+	for field, value := range r.HGetAll(s.orm, s.listName+asyncStatsSuffix) {
+		asUint64, _ := strconv.ParseUint(value, 10, 64)
+		switch field {
+		case asyncStatsEvents:
+			stats.EventsApplied = asUint64
+		case asyncStatsBatches:
+			stats.BatchesCommitted = asUint64
+		case asyncStatsOneByOne:
+			stats.OneByOneFallbacks = asUint64
+		default:
+			if strings.HasPrefix(field, asyncStatsSkippedPrefix) {
+				code, err := strconv.ParseUint(field[len(asyncStatsSkippedPrefix):], 10, 16)
+				if err == nil {
+					stats.SkippedErrorCodes[uint16(code)] = asUint64
+				}
+			}
+		}
+	}
+	now := time.Now()
+	rateKeys := make([]string, asyncStatsRateWindow)
+	for i := range rateKeys {
+		rateKeys[i] = asyncStatsRateKey(s.listName, now.Unix()-int64(i+1))
+	}
+	applied := uint64(0)
+	for _, value := range r.MGet(s.orm, rateKeys...) {
+		asString, _ := value.(string)
+		asUint64, _ := strconv.ParseUint(asString, 10, 64)
+		applied += asUint64
+	}
+	stats.EventsPerSecond = float64(applied) / asyncStatsRateWindow
+	var oldest int64
+	head := r.LRange(s.orm, s.listName+asyncTimestampsSuffix, 0, 0)
+	if len(head) > 0 {
+		oldest, _ = strconv.ParseInt(head[0][strings.Index(head[0], ":")+1:], 10, 64)
+	}
+	for _, stream := range s.streams() {
+		messages := r.XRange(s.orm, stream, "-", "+", 1)
+		if len(messages) == 0 {
+			continue
+		}
+		id, _ := strconv.ParseInt(messages[0].ID[0:strings.Index(messages[0].ID, "-")], 10, 64)
+		if oldest == 0 || id < oldest {
+			oldest = id
+		}
+	}
+	if oldest > 0 && stats.Pending > 0 {
+		stats.OldestEventAge = now.Sub(time.UnixMilli(oldest))
+		if stats.OldestEventAge < 0 {
+			stats.OldestEventAge = 0
+		}
+	}
+	return stats
+}
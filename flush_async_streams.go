@@ -0,0 +1,234 @@
+package beeorm
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/bsm/redislock"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/redis/go-redis/v9"
+)
+
+const asyncStreamsGroup = "beeorm"
+const asyncStreamsClaimMinIdle = time.Minute
+const asyncStreamsEventField = "e"
+
+type AsyncFlushStreamsOptions struct {
+	Partitions         int
+	ConsumerPartitions int
+	ClaimMinIdle       time.Duration
+}
+
+func asyncStreamName(list string, partition int) string {
+	return list + ":stream:" + strconv.Itoa(partition)
+}
+
+func (e *engineImplementation) asyncStreamPartition(id uint64) int {
+	return int(id % uint64(e.asyncFlushStreams.Partitions))
+}
+
+func asyncEventEntityID(event asyncTemporaryQueueEvent) uint64 {
+	if structured, isStructured := event.structured(); isStructured {
+		id, _ := strconv.ParseUint(structured.ID, 10, 64)
+		return id
+	}
+	if len(event) > 0 {
+		if flushed, has := event[0].(asyncFlushedEvents); has {
+			if len(flushed) > 0 {
+				id, _ := strconv.ParseUint(flushed[0].ID, 10, 64)
+				return id
+			}
+			event = event[1:]
+		}
+	}
+	if len(event) == 0 {
+		return 0
+	}
+	sql, _ := event[0].(string)
+	params := event[1:]
+	var id any
+	if strings.HasPrefix(sql, "INSERT INTO ") && len(params) > 0 {
+		id = params[0]
+	} else if pos := strings.Index(sql, " WHERE ID IN ("); pos > 0 {
+		ids := sql[pos+14:]
+		end := strings.IndexAny(ids, ",)")
+		if end > 0 {
+			id = ids[0:end]
+		}
+	} else if strings.HasSuffix(sql, " WHERE ID = ?") && len(params) > 0 {
+		id = params[len(params)-1]
+	} else if strings.Contains(sql, " WHERE ID = ? AND ") && len(params) > 1 {
+		id = params[len(params)-2]
+	}
+	if id == nil {
+		return 0
+	}
+	asUint64, _ := strconv.ParseUint(fmt.Sprintf("%v", id), 10, 64)
+	return asUint64
+}
+
+func asyncJSONEventEntityID(value string) uint64 {
+	if isStructuredAsyncEvent(value) {
+		event := &asyncEvent{}
+		_ = jsoniter.ConfigFastest.UnmarshalFromString(value, event)
+		id, _ := strconv.ParseUint(event.ID, 10, 64)
+		return id
+	}
+	flushed, value := splitAsyncEvent(value)
+	var event asyncTemporaryQueueEvent
+	if flushed != "" {
+		var flushedEvents asyncFlushedEvents
+		_ = jsoniter.ConfigFastest.UnmarshalFromString(flushed, &flushedEvents)
+		event = append(event, flushedEvents)
+	}
+	var data []any
+	_ = jsoniter.ConfigFastest.UnmarshalFromString(value, &data)
+	return asyncEventEntityID(append(event, data...))
+}
+
+func consumeAsyncFlushStreams(orm ORM, block bool) error {
+	engine := orm.Engine().(*engineImplementation)
+	errorMutex := sync.Mutex{}
+	waitGroup := &sync.WaitGroup{}
+	ctxNoCancel := orm.CloneWithContext(context.Background())
+	consumer, _ := os.Hostname()
+	if consumer == "" {
+		consumer = "beeorm"
+	}
+	var stop uint32
+	var globalError error
+	var locks []*Lock
+	defer func() {
+		for _, lock := range locks {
+			lock.Release(orm)
+		}
+	}()
+	done := make(chan struct{})
+	defer close(done)
+	visited := make(map[string]bool)
+	for _, schema := range orm.Engine().Registry().Entities() {
+		db := schema.GetDB()
+		r := orm.Engine().Redis(schema.getForcedRedisCode())
+		list := schema.(*entitySchema).asyncCacheKey
+		if visited[r.GetCode()+":"+list] {
+			continue
+		}
+		visited[r.GetCode()+":"+list] = true
+		partitions := engine.asyncFlushStreams.Partitions
+		consumerPartitions := engine.asyncFlushStreams.ConsumerPartitions
+		offset := rand.Intn(partitions)
+		consumed := 0
+		for i := 0; i < partitions && (consumerPartitions <= 0 || consumed < consumerPartitions); i++ {
+			stream := asyncStreamName(list, (offset+i)%partitions)
+			// one consumer per partition keeps events of the same entity in order,
+			// other processes consume remaining partitions in parallel
+			lock, lockObtained := r.GetLocker().Obtain(orm, asyncConsumerLockName+":"+stream, time.Minute, 0)
+			if !lockObtained {
+				continue
+			}
+			consumed++
+			locks = append(locks, lock)
+			obtained := &atomic.Bool{}
+			obtained.Store(true)
+			go func() {
+				ticker := time.NewTicker(time.Second * 50)
+				defer ticker.Stop()
+				for {
+					select {
+					case <-done:
+						return
+					case <-ticker.C:
+						if !lock.Refresh(orm, time.Minute) {
+							obtained.Store(false)
+							return
+						}
+					}
+				}
+			}()
+			waitGroup.Add(1)
+			go func() {
+				defer func() {
+					if rec := recover(); rec != nil {
+						atomic.AddUint32(&stop, 1)
+						asError, isError := rec.(error)
+						if !isError {
+							asError = fmt.Errorf("%v", rec)
+						}
+						errorMutex.Lock()
+						if globalError == nil {
+							globalError = asError
+						}
+						errorMutex.Unlock()
+					}
+				}()
+				consumeAsyncStreamEvents(orm.Context(), ctxNoCancel.Clone(), list, stream, consumer, db, r, block, waitGroup, obtained, &stop)
+			}()
+		}
+	}
+	if len(locks) == 0 {
+		return redislock.ErrNotObtained
+	}
+	waitGroup.Wait()
+	return globalError
+}
+
+func consumeAsyncStreamEvents(context context.Context, orm ORM, list, stream, consumer string, db DB, r RedisCache,
+	block bool, waitGroup *sync.WaitGroup, lockObtained *atomic.Bool, stop *uint32) {
+	defer waitGroup.Done()
+	r.XGroupCreateMkStream(orm, stream, asyncStreamsGroup, "0")
+	minIdle := orm.Engine().(*engineImplementation).asyncFlushStreams.ClaimMinIdle
+	for {
+		if context.Err() != nil || !lockObtained.Load() || atomic.LoadUint32(stop) > 0 {
+			return
+		}
+		messages := readAsyncStreamMessages(orm, stream, consumer, r, minIdle)
+		if len(messages) > 0 {
+			ids := make([]string, len(messages))
+			values := make([]string, 0, len(messages))
+			for i, message := range messages {
+				ids[i] = message.ID
+				value, _ := message.Values[asyncStreamsEventField].(string)
+				values = append(values, value)
+			}
+			handleAsyncEvents(context, orm, list, db, r, values, func(from, to int) {
+				r.XAck(orm, stream, asyncStreamsGroup, ids[from:to]...)
+				r.XDel(orm, stream, ids[from:to]...)
+			})
+		}
+		if len(messages) < asyncConsumerPage {
+			if !block || context.Err() != nil {
+				return
+			}
+			time.Sleep(orm.Engine().Registry().(*engineRegistryImplementation).asyncConsumerBlockTime)
+		}
+	}
+}
+
+func readAsyncStreamMessages(orm ORM, stream, consumer string, r RedisCache, minIdle time.Duration) []redis.XMessage {
+	streams := r.XReadGroup(orm, &redis.XReadGroupArgs{Group: asyncStreamsGroup, Consumer: consumer,
+		Streams: []string{stream, "0"}, Count: asyncConsumerPage, Block: -1})
+	if len(streams) > 0 && len(streams[0].Messages) > 0 {
+		return streams[0].Messages
+	}
+	messages, _ := r.XAutoClaim(orm, &redis.XAutoClaimArgs{Stream: stream, Group: asyncStreamsGroup, Consumer: consumer,
+		MinIdle: minIdle, Start: "0-0", Count: asyncConsumerPage})
+	if len(messages) > 0 {
+		return messages
+	}
+	if r.XPending(orm, stream, asyncStreamsGroup).Count > 0 {
+		return nil
+	}
+	streams = r.XReadGroup(orm, &redis.XReadGroupArgs{Group: asyncStreamsGroup, Consumer: consumer,
+		Streams: []string{stream, ">"}, Count: asyncConsumerPage, Block: -1})
+	if len(streams) > 0 {
+		return streams[0].Messages
+	}
+	return nil
+}
@@ -0,0 +1,137 @@
+package beeorm
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/bsm/redislock"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+)
+
+type flushAsyncStreamsEntity struct {
+	ID   uint64 `orm:"localCache;redisCache"`
+	Name string `orm:"required"`
+	Age  uint8
+}
+
+func TestAsyncFlushStreams(t *testing.T) {
+	registry := NewRegistry()
+	registry.SetAsyncFlushStreams(&AsyncFlushStreamsOptions{Partitions: 3})
+	var entity *flushAsyncStreamsEntity
+	orm := PrepareTables(t, registry, entity)
+	schema := getEntitySchema[flushAsyncStreamsEntity](orm)
+	r := orm.Engine().Redis(DefaultPoolCode)
+
+	entities := make([]*flushAsyncStreamsEntity, 10)
+	for i := range entities {
+		entities[i] = NewEntity[flushAsyncStreamsEntity](orm)
+		entities[i].Name = "name " + strconv.Itoa(i)
+	}
+	assert.NoError(t, orm.FlushAsync())
+	for i := range entities {
+		entities[i] = EditEntity(orm, entities[i])
+		entities[i].Age = uint8(i)
+	}
+	assert.NoError(t, orm.FlushAsync())
+	DeleteEntity(orm, entities[0])
+	DeleteEntity(orm, entities[1])
+	DeleteEntity(orm, entities[2])
+	assert.NoError(t, orm.FlushAsync())
+
+	stop := ConsumeAsyncBuffer(orm, func(err error) {
+		panic(err)
+	})
+	stop()
+	assert.Equal(t, int64(0), r.LLen(orm, schema.asyncCacheKey))
+	total := int64(0)
+	for i := 0; i < 3; i++ {
+		partition := r.XLen(orm, asyncStreamName(schema.asyncCacheKey, i))
+		assert.Greater(t, partition, int64(0))
+		total += partition
+	}
+	assert.Equal(t, int64(23), total)
+	stats := ReadAsyncFlushEvents(orm)
+	for _, stat := range stats {
+		if stat.RedisList() == schema.asyncCacheKey {
+			assert.Equal(t, uint64(23), stat.EventsCount())
+			assert.Len(t, stat.Events(100), 23)
+		}
+	}
+
+	streams := orm.Engine().(*engineImplementation).asyncFlushStreams
+	streams.ConsumerPartitions = 1
+	assert.NoError(t, ConsumeAsyncFlushEvents(orm, false))
+	consumed := 0
+	for i := 0; i < 3; i++ {
+		if r.XLen(orm, asyncStreamName(schema.asyncCacheKey, i)) == 0 {
+			consumed++
+		}
+	}
+	assert.Equal(t, 1, consumed)
+	streams.ConsumerPartitions = 0
+
+	lock, obtained := r.GetLocker().Obtain(orm, asyncConsumerLockName+":"+asyncStreamName(schema.asyncCacheKey, 0), time.Minute, 0)
+	assert.True(t, obtained)
+	assert.NoError(t, ConsumeAsyncFlushEvents(orm, false))
+	assert.Greater(t, r.XLen(orm, asyncStreamName(schema.asyncCacheKey, 0)), int64(0))
+	assert.Equal(t, int64(0), r.XLen(orm, asyncStreamName(schema.asyncCacheKey, 1)))
+	lock.Release(orm)
+
+	assert.NoError(t, ConsumeAsyncFlushEvents(orm, false))
+	for i := 0; i < 3; i++ {
+		assert.Equal(t, int64(0), r.XLen(orm, asyncStreamName(schema.asyncCacheKey, i)))
+	}
+	schema.DisableCache(true, true)
+	rows := Search[flushAsyncStreamsEntity](orm, NewWhere("1 ORDER BY ID"), nil)
+	assert.Equal(t, 7, rows.Len())
+	for i := 3; rows.Next(); i++ {
+		assert.Equal(t, entities[i].ID, rows.Entity().ID)
+		assert.Equal(t, uint8(i), rows.Entity().Age)
+	}
+
+	var locks []*Lock
+	for i := 0; i < 3; i++ {
+		lock, obtained = r.GetLocker().Obtain(orm, asyncConsumerLockName+":"+asyncStreamName(schema.asyncCacheKey, i), time.Minute, 0)
+		assert.True(t, obtained)
+		locks = append(locks, lock)
+	}
+	assert.ErrorIs(t, ConsumeAsyncFlushEvents(orm, false), redislock.ErrNotObtained)
+	for _, lock = range locks {
+		lock.Release(orm)
+	}
+
+	entity = NewEntity[flushAsyncStreamsEntity](orm)
+	entity.Name = "crashed"
+	assert.NoError(t, orm.FlushAsync())
+	stop = ConsumeAsyncBuffer(orm, func(err error) {
+		panic(err)
+	})
+	stop()
+	stream := asyncStreamName(schema.asyncCacheKey, orm.Engine().(*engineImplementation).asyncStreamPartition(entity.ID))
+	r.XGroupCreateMkStream(orm, stream, asyncStreamsGroup, "0")
+	pending := r.XReadGroup(orm, &redis.XReadGroupArgs{Group: asyncStreamsGroup, Consumer: "crashed",
+		Streams: []string{stream, ">"}, Count: 10, Block: -1})
+	assert.Len(t, pending, 1)
+	assert.Equal(t, int64(1), r.XPending(orm, stream, asyncStreamsGroup).Count)
+	streams.ClaimMinIdle = time.Millisecond
+	time.Sleep(time.Millisecond * 5)
+	assert.NoError(t, ConsumeAsyncFlushEvents(orm, false))
+	assert.Equal(t, int64(0), r.XLen(orm, stream))
+	assert.Equal(t, int64(0), r.XPending(orm, stream, asyncStreamsGroup).Count)
+	_, found := GetByID[flushAsyncStreamsEntity](orm, entity.ID)
+	assert.True(t, found)
+}
+
+func TestAsyncEventEntityID(t *testing.T) {
+	assert.Equal(t, uint64(12), asyncEventEntityID(asyncTemporaryQueueEvent{"INSERT INTO `a`(`ID`,`Name`) VALUES(?,?)", "12", "a"}))
+	assert.Equal(t, uint64(13), asyncEventEntityID(asyncTemporaryQueueEvent{"UPDATE `a` SET `Name`=? WHERE ID = ?", "a", "13"}))
+	assert.Equal(t, uint64(14), asyncEventEntityID(asyncTemporaryQueueEvent{"UPDATE `a` SET `Name`=? WHERE ID = ? AND `Version` = ?", "a", "14", "2"}))
+	assert.Equal(t, uint64(15), asyncEventEntityID(asyncTemporaryQueueEvent{"DELETE FROM `a` WHERE ID IN (15,18)"}))
+	assert.Equal(t, uint64(16), asyncEventEntityID(asyncTemporaryQueueEvent{asyncFlushedEvents{{ID: "16"}}, "DELETE FROM `a` WHERE ID IN (17)"}))
+	assert.Equal(t, uint64(0), asyncEventEntityID(asyncTemporaryQueueEvent{"TRUNCATE `a`"}))
+	assert.Equal(t, uint64(19), asyncEventEntityID(asyncTemporaryQueueEvent{&asyncEvent{ID: "19"}}))
+	assert.Equal(t, uint64(20), asyncJSONEventEntityID(`{"v":2,"s":"a","t":1,"i":"20"}`))
+	assert.Equal(t, uint64(21), asyncJSONEventEntityID(`["INSERT INTO `+"`a`(`ID`)"+` VALUES(?)","21"]`))
+}
@@ -0,0 +1,72 @@
+package beeorm
+
+import "reflect"
+
+type EntityBeforeFlush interface {
+	BeforeFlush(orm ORM, flushType FlushType) error
+}
+
+type EntityAfterFlush interface {
+	AfterFlush(orm ORM, flushType FlushType)
+}
+
+func runBeforeFlushHook(orm ORM, flush EntityFlush) error {
+	if f, isFields := flush.(*editableFields); isFields {
+		return f.runBeforeFlushHook(orm)
+	}
+	hook, has := beforeFlushHookEntity(flush).(EntityBeforeFlush)
+	if !has {
+		return nil
+	}
+	return hook.BeforeFlush(orm, flush.flushType())
+}
+
+func beforeFlushHookEntity(flush EntityFlush) any {
+	switch f := flush.(type) {
+	case *editableFields:
+		return f.value.Interface()
+	case *removableEntity:
+		return f.source
+	case entityFlushInsert:
+		return f.getEntity()
+	case entityFlushUpdate:
+		return f.getEntity()
+	}
+	return nil
+}
+
+func (f *editableFields) runBeforeFlushHook(orm ORM) error {
+	_, has := f.value.Interface().(EntityBeforeFlush)
+	if !has {
+		return nil
+	}
+	value := reflect.New(f.schema.t)
+	copyEntity(f.value.Elem(), value.Elem(), f.schema.fields, true)
+	for column, v := range f.newBind {
+		f.schema.fieldSetters[column](v, value.Elem())
+	}
+	err := value.Interface().(EntityBeforeFlush).BeforeFlush(orm, Update)
+	if err != nil {
+		return err
+	}
+	newBind := Bind{}
+	oldBind := Bind{}
+	err = fillBindFromTwoSources(orm, newBind, oldBind, Bind{}, Bind{}, value.Elem(), f.value.Elem(), f.schema.fields, "")
+	if err != nil {
+		return err
+	}
+	for column, v := range newBind {
+		f.newBind[column] = v
+		f.oldBind[column] = oldBind[column]
+	}
+	return nil
+}
+
+func (orm *ormImplementation) appendAfterFlushHook(entity any, flushType FlushType) {
+	hook, has := entity.(EntityAfterFlush)
+	if has && orm.plan == nil {
+		orm.flushPostActions = append(orm.flushPostActions, func(o ORM) {
+			hook.AfterFlush(o, flushType)
+		})
+	}
+}
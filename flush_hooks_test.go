@@ -0,0 +1,115 @@
+package beeorm
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var flushHooksAfter = make(map[FlushType]int)
+
+type flushHooksEntity struct {
+	ID      uint64 `orm:"localCache;redisCache"`
+	Name    string
+	Slug    string
+	Counter uint16
+}
+
+func (e *flushHooksEntity) BeforeFlush(_ ORM, flushType FlushType) error {
+	if e.Name == "invalid" {
+		return errors.New("invalid name")
+	}
+	if flushType != Delete {
+		e.Slug = strings.ToLower(e.Name)
+		e.Counter++
+	}
+	return nil
+}
+
+func (e *flushHooksEntity) AfterFlush(_ ORM, flushType FlushType) {
+	flushHooksAfter[flushType]++
+}
+
+func TestFlushHooksNoCache(t *testing.T) {
+	testFlushHooks(t, false, false, false)
+}
+
+func TestFlushHooksLocalCache(t *testing.T) {
+	testFlushHooks(t, false, true, false)
+}
+
+func TestFlushHooksRedis(t *testing.T) {
+	testFlushHooks(t, false, false, true)
+}
+
+func TestFlushHooksLocalCacheRedis(t *testing.T) {
+	testFlushHooks(t, false, true, true)
+}
+
+func TestFlushHooksNoCacheAsync(t *testing.T) {
+	testFlushHooks(t, true, false, false)
+}
+
+func TestFlushHooksLocalCacheRedisAsync(t *testing.T) {
+	testFlushHooks(t, true, true, true)
+}
+
+func testFlushHooks(t *testing.T, async, local, redis bool) {
+	var entity *flushHooksEntity
+	orm := PrepareTables(t, NewRegistry(), entity)
+	schema := GetEntitySchema[flushHooksEntity](orm)
+	schema.DisableCache(!local, !redis)
+	flushHooksAfter = make(map[FlushType]int)
+
+	flush := func() error {
+		if async {
+			err := orm.FlushAsync()
+			if err != nil {
+				return err
+			}
+			return runAsyncConsumer(orm, false)
+		}
+		return orm.Flush()
+	}
+
+	entity = NewEntity[flushHooksEntity](orm)
+	entity.Name = "Tom"
+	assert.NoError(t, flush())
+	id := entity.ID
+	assert.Equal(t, 1, flushHooksAfter[Insert])
+	entity, _ = GetByID[flushHooksEntity](orm, id)
+	assert.Equal(t, "tom", entity.Slug)
+	assert.Equal(t, uint16(1), entity.Counter)
+
+	entity = EditEntity(orm, entity)
+	entity.Name = "John"
+	assert.NoError(t, flush())
+	assert.Equal(t, 1, flushHooksAfter[Update])
+	entity, _ = GetByID[flushHooksEntity](orm, id)
+	assert.Equal(t, "john", entity.Slug)
+	assert.Equal(t, uint16(2), entity.Counter)
+
+	assert.NoError(t, EditEntityField(orm, entity, "Name", "Adam"))
+	assert.NoError(t, flush())
+	assert.Equal(t, 2, flushHooksAfter[Update])
+	entity, _ = GetByID[flushHooksEntity](orm, id)
+	assert.Equal(t, "Adam", entity.Name)
+	assert.Equal(t, "adam", entity.Slug)
+	assert.Equal(t, uint16(3), entity.Counter)
+
+	entity = EditEntity(orm, entity)
+	entity.Name = "invalid"
+	assert.EqualError(t, flush(), "invalid name")
+	assert.Equal(t, 2, flushHooksAfter[Update])
+	orm.ClearFlush()
+	entity, _ = GetByID[flushHooksEntity](orm, id)
+	assert.Equal(t, "Adam", entity.Name)
+
+	DeleteEntity(orm, entity)
+	assert.NoError(t, flush())
+	assert.Equal(t, 1, flushHooksAfter[Delete])
+	_, found := GetByID[flushHooksEntity](orm, id)
+	assert.False(t, found)
+}
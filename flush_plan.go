@@ -0,0 +1,141 @@
+package beeorm
+
+import (
+	"fmt"
+)
+
+type FlushPlanQuery struct {
+	Pool       string
+	Query      string
+	Parameters []any
+}
+
+type FlushPlanRedisCommand struct {
+	Pool    string
+	Command string
+}
+
+type FlushPlanLocalCacheChange struct {
+	Cache     string
+	Operation string
+	Key       string
+	ID        uint64
+}
+
+type FlushPlanAsyncEvent struct {
+	Schema     EntitySchema
+	Query      string
+	Parameters []any
+}
+
+type FlushPlanHook struct {
+	Schema    EntitySchema
+	ID        uint64
+	FlushType FlushType
+}
+
+type FlushPlan struct {
+	Queries           []FlushPlanQuery
+	RedisCommands     []FlushPlanRedisCommand
+	LocalCacheChanges []FlushPlanLocalCacheChange
+	AsyncEvents       []FlushPlanAsyncEvent
+	BeforeFlushHooks  []FlushPlanHook
+}
+
+type flushPlanDB struct {
+	DBBase
+	plan *FlushPlan
+}
+
+func (db *flushPlanDB) Exec(_ ORM, query string, args ...any) ExecResult {
+	db.plan.Queries = append(db.plan.Queries, FlushPlanQuery{Pool: db.GetConfig().GetCode(), Query: query, Parameters: args})
+	return &flushPlanExecResult{}
+}
+
+type flushPlanExecResult struct{}
+
+func (r *flushPlanExecResult) LastInsertId() uint64 {
+	return 0
+}
+
+func (r *flushPlanExecResult) RowsAffected() uint64 {
+	return 1
+}
+
+func (orm *ormImplementation) FlushPlan() (plan FlushPlan, err error) {
+	orm.mutexFlush.Lock()
+	defer orm.mutexFlush.Unlock()
+	if orm.trackedEntities == nil || orm.trackedEntities.Size() == 0 {
+		return plan, nil
+	}
+	rollbackReferenceActions, err := orm.handleReferenceActions()
+	if err != nil {
+		return plan, err
+	}
+	defer rollbackReferenceActions()
+	orm.plan = &plan
+	defer func() {
+		orm.plan = nil
+		orm.flushDBActions = nil
+		orm.flushDBCommitActions = nil
+		orm.asyncEventsToReserve = nil
+		orm.flushPostActions = orm.flushPostActions[0:0]
+		orm.redisPipeLines = nil
+		if rec := recover(); rec != nil {
+			asErr, isErr := rec.(error)
+			if !isErr {
+				asErr = fmt.Errorf("%v", rec)
+			}
+			plan = FlushPlan{}
+			err = asErr
+		}
+	}()
+	err = orm.buildFlushActions(false)
+	if err != nil {
+		return FlushPlan{}, err
+	}
+	for code, actions := range orm.flushDBActions {
+		db := &flushPlanDB{DBBase: orm.Engine().DB(code), plan: orm.plan}
+		for _, action := range actions {
+			action(db)
+		}
+	}
+	orm.runDBCommitActions()
+	for _, pipeline := range orm.redisPipeLines {
+		pipeline.Exec(orm)
+	}
+	for _, action := range orm.flushPostActions {
+		action(orm)
+	}
+	return plan, nil
+}
+
+func (orm *ormImplementation) enqueueAsyncEvent(schema *entitySchema, event asyncTemporaryQueueEvent) {
+	if orm.plan != nil {
+		planned := FlushPlanAsyncEvent{Schema: schema}
+		if structured, isStructured := event.structured(); isStructured {
+			planned.Query, planned.Parameters = structured.buildSQL(schema)
+		} else if len(event) > 0 {
+			planned.Query, _ = event[0].(string)
+			planned.Parameters = event[1:]
+		}
+		orm.plan.AsyncEvents = append(orm.plan.AsyncEvents, planned)
+		return
+	}
+	publishAsyncEvent(orm, schema, event)
+}
+
+func (plan *FlushPlan) addBeforeFlushHook(flush EntityFlush) {
+	if _, has := beforeFlushHookEntity(flush).(EntityBeforeFlush); has {
+		plan.BeforeFlushHooks = append(plan.BeforeFlushHooks, FlushPlanHook{Schema: flush.Schema(), ID: flush.ID(), FlushType: flush.flushType()})
+	}
+}
+
+func (lc *localCache) addToFlushPlan(orm ORM, operation, key string, id uint64) bool {
+	plan := orm.(*ormImplementation).plan
+	if plan == nil {
+		return false
+	}
+	plan.LocalCacheChanges = append(plan.LocalCacheChanges, FlushPlanLocalCacheChange{Cache: lc.config.code, Operation: operation, Key: key, ID: id})
+	return true
+}
@@ -0,0 +1,120 @@
+package beeorm
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type flushPlanEntity struct {
+	ID   uint64 `orm:"localCache;redisCache"`
+	Name string `orm:"unique=Name;cached"`
+}
+
+func TestFlushPlanNoCache(t *testing.T) {
+	testFlushPlan(t, false, false)
+}
+
+func TestFlushPlanLocalCache(t *testing.T) {
+	testFlushPlan(t, true, false)
+}
+
+func TestFlushPlanRedis(t *testing.T) {
+	testFlushPlan(t, false, true)
+}
+
+func TestFlushPlanLocalCacheRedis(t *testing.T) {
+	testFlushPlan(t, true, true)
+}
+
+func testFlushPlan(t *testing.T, local, redis bool) {
+	var entity *flushPlanEntity
+	orm := PrepareTables(t, NewRegistry(), entity)
+	schema := GetEntitySchema[flushPlanEntity](orm)
+	schema.DisableCache(!local, !redis)
+
+	plan, err := orm.FlushPlan()
+	assert.NoError(t, err)
+	assert.Len(t, plan.Queries, 0)
+
+	entity = NewEntity[flushPlanEntity](orm)
+	entity.Name = "a"
+	plan, err = orm.FlushPlan()
+	assert.NoError(t, err)
+	assert.Len(t, plan.Queries, 1)
+	assert.True(t, strings.HasPrefix(plan.Queries[0].Query, "INSERT INTO `flushPlanEntity`"))
+	assert.Equal(t, DefaultPoolCode, plan.Queries[0].Pool)
+	assert.Equal(t, []any{entity.ID, "a"}, plan.Queries[0].Parameters)
+	if local {
+		assert.Equal(t, []FlushPlanLocalCacheChange{{Cache: schema.(*entitySchema).getCacheKey(), Operation: "SET ENTITY", ID: entity.ID}}, plan.LocalCacheChanges)
+	} else {
+		assert.Len(t, plan.LocalCacheChanges, 0)
+	}
+	hasRPush := false
+	for _, command := range plan.RedisCommands {
+		if strings.HasPrefix(command.Command, "RPUSH ") {
+			hasRPush = true
+		}
+	}
+	assert.Equal(t, redis, hasRPush)
+	total := 0
+	orm.Engine().DB(DefaultPoolCode).QueryRow(orm, NewWhere("SELECT COUNT(*) FROM `flushPlanEntity`"), &total)
+	assert.Equal(t, 0, total)
+
+	assert.NoError(t, orm.Flush())
+	_, found := GetByID[flushPlanEntity](orm, entity.ID)
+	assert.True(t, found)
+
+	entity = EditEntity(orm, entity)
+	entity.Name = "b"
+	plan, err = orm.FlushPlan()
+	assert.NoError(t, err)
+	assert.Len(t, plan.Queries, 1)
+	assert.Equal(t, "UPDATE `flushPlanEntity` SET `Name`=? WHERE ID = ?", plan.Queries[0].Query)
+	assert.Equal(t, []any{"b", entity.ID}, plan.Queries[0].Parameters)
+	assert.NoError(t, orm.Flush())
+	entity, _ = GetByID[flushPlanEntity](orm, entity.ID)
+	assert.Equal(t, "b", entity.Name)
+}
+
+type flushPlanVersionEntity struct {
+	ID      uint64 `orm:"localCache;redisCache"`
+	Name    string
+	Hooks   uint16
+	Version uint32 `orm:"version"`
+}
+
+func (e *flushPlanVersionEntity) BeforeFlush(_ ORM, _ FlushType) error {
+	e.Hooks++
+	return nil
+}
+
+func TestFlushPlanVersionWithHooks(t *testing.T) {
+	var entity *flushPlanVersionEntity
+	orm := PrepareTables(t, NewRegistry(), entity)
+
+	entity = NewEntity[flushPlanVersionEntity](orm)
+	entity.Name = "a"
+	assert.NoError(t, orm.Flush())
+	assert.Equal(t, uint16(1), entity.Hooks)
+
+	entity = EditEntity(orm, entity)
+	entity.Name = "b"
+	for i := 0; i < 2; i++ {
+		plan, err := orm.FlushPlan()
+		assert.NoError(t, err)
+		assert.Len(t, plan.BeforeFlushHooks, 1)
+		assert.Equal(t, Update, plan.BeforeFlushHooks[0].FlushType)
+		assert.Equal(t, entity.ID, plan.BeforeFlushHooks[0].ID)
+		assert.Len(t, plan.Queries, 1)
+		assert.Contains(t, plan.Queries[0].Query, " AND `Version` = ?")
+		assert.Equal(t, uint32(0), entity.Version)
+		assert.Equal(t, uint16(1), entity.Hooks)
+	}
+	assert.NoError(t, orm.Flush())
+	entity, _ = GetByID[flushPlanVersionEntity](orm, entity.ID)
+	assert.Equal(t, "b", entity.Name)
+	assert.Equal(t, uint16(2), entity.Hooks)
+	assert.Equal(t, uint32(1), entity.Version)
+}
@@ -0,0 +1,67 @@
+package beeorm
+
+import (
+	"errors"
+	"math/rand"
+	"slices"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+var mySQLErrorCodesToRetry = []uint16{
+	1205, // Lock wait timeout exceeded; try restarting transaction
+	1213, // Deadlock found when trying to get lock; try restarting transaction
+}
+
+type FlushRetryPolicy struct {
+	MaxAttempts int
+	Backoff     time.Duration
+	MaxBackoff  time.Duration
+	Jitter      time.Duration
+}
+
+func (p *FlushRetryPolicy) delay(attempt int) time.Duration {
+	delay := p.Backoff
+	for i := 1; i < attempt && (p.MaxBackoff == 0 || delay < p.MaxBackoff); i++ {
+		delay *= 2
+	}
+	if p.MaxBackoff > 0 && delay > p.MaxBackoff {
+		delay = p.MaxBackoff
+	}
+	if p.Jitter > 0 {
+		delay += time.Duration(rand.Int63n(int64(p.Jitter)))
+	}
+	return delay
+}
+
+func (orm *ormImplementation) SetFlushRetryPolicy(policy *FlushRetryPolicy) {
+	orm.flushRetryPolicy = policy
+}
+
+func (orm *ormImplementation) getFlushRetryPolicy() *FlushRetryPolicy {
+	if orm.flushRetryPolicy != nil {
+		return orm.flushRetryPolicy
+	}
+	return orm.engine.flushRetryPolicy
+}
+
+func (orm *ormImplementation) executeDBActionsWithRetry() error {
+	policy := orm.getFlushRetryPolicy()
+	for attempt := 1; ; attempt++ {
+		err := orm.executeDBActions()
+		if err == nil || policy == nil || orm.inTransaction || attempt >= policy.MaxAttempts || !isRetryableMySQLError(err) {
+			return err
+		}
+		select {
+		case <-orm.Context().Done():
+			return orm.Context().Err()
+		case <-time.After(policy.delay(attempt)):
+		}
+	}
+}
+
+func isRetryableMySQLError(err error) bool {
+	var mySQLError *mysql.MySQLError
+	return errors.As(err, &mySQLError) && slices.Contains(mySQLErrorCodesToRetry, mySQLError.Number)
+}
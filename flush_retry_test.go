@@ -0,0 +1,128 @@
+package beeorm
+
+import (
+	"context"
+	"database/sql"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/stretchr/testify/assert"
+)
+
+type flushRetryEntity struct {
+	ID   uint64 `orm:"localCache"`
+	Name string
+}
+
+func TestFlushRetry(t *testing.T) {
+	var entity *flushRetryEntity
+	orm := PrepareTables(t, NewRegistry(), entity)
+	db := orm.Engine().DB(DefaultPoolCode)
+	originDB := db.GetDBClient()
+	defer db.SetMockDBClient(originDB)
+	failures := 0
+	db.SetMockDBClient(&MockDBClient{OriginDB: originDB, ExecMock: func(query string, args ...any) (sql.Result, error) {
+		if failures > 0 {
+			failures--
+			return nil, &mysql.MySQLError{Number: 1213, Message: "Deadlock found when trying to get lock; try restarting transaction"}
+		}
+		return originDB.Exec(query, args...)
+	}})
+
+	failures = 1
+	entity = NewEntity[flushRetryEntity](orm)
+	entity.Name = "a"
+	assert.EqualError(t, orm.Flush(), "Error 1213: Deadlock found when trying to get lock; try restarting transaction")
+	orm.ClearFlush()
+
+	orm.SetFlushRetryPolicy(&FlushRetryPolicy{MaxAttempts: 3, Backoff: time.Millisecond, Jitter: time.Millisecond})
+	failures = 2
+	entity = NewEntity[flushRetryEntity](orm)
+	entity.Name = "b"
+	assert.NoError(t, orm.Flush())
+	assert.Equal(t, 0, failures)
+	entity, found := GetByID[flushRetryEntity](orm, entity.ID)
+	assert.True(t, found)
+	assert.Equal(t, "b", entity.Name)
+
+	failures = 3
+	entity = NewEntity[flushRetryEntity](orm)
+	entity.Name = "c"
+	assert.EqualError(t, orm.Flush(), "Error 1213: Deadlock found when trying to get lock; try restarting transaction")
+	assert.Equal(t, 0, failures)
+	_, found = GetByID[flushRetryEntity](orm, entity.ID)
+	assert.False(t, found)
+
+	failures = 1
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*50)
+	defer cancel()
+	ormWithTimeout := orm.CloneWithContext(ctx)
+	ormWithTimeout.SetFlushRetryPolicy(&FlushRetryPolicy{MaxAttempts: 3, Backoff: time.Hour})
+	entity = NewEntity[flushRetryEntity](ormWithTimeout)
+	entity.Name = "d"
+	start := time.Now()
+	assert.ErrorIs(t, ormWithTimeout.Flush(), context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+type flushRetryUpsertEntity struct {
+	ID   uint64 `orm:"redisCache"`
+	Name string `orm:"required;unique=Name;cached"`
+	Age  uint8  `orm:"index=Age;cached"`
+}
+
+type flushRetryDeadlockPlugin struct {
+	orm      *ormImplementation
+	failures int
+}
+
+func (p *flushRetryDeadlockPlugin) EntityFlush(schema EntitySchema, entity reflect.Value, _, _ Bind, _ Engine) (PostFlushAction, error) {
+	if p.orm == nil || entity.FieldByName("Name").String() != "b" {
+		return nil, nil
+	}
+	p.orm.appendDBAction(schema, func(_ DBBase) {
+		if p.failures > 0 {
+			p.failures--
+			panic(&mysql.MySQLError{Number: 1213, Message: "Deadlock found when trying to get lock; try restarting transaction"})
+		}
+	})
+	return nil, nil
+}
+
+func TestFlushRetryUpsertCache(t *testing.T) {
+	var entity *flushRetryUpsertEntity
+	registry := NewRegistry()
+	plugin := &flushRetryDeadlockPlugin{}
+	registry.RegisterPlugin(plugin)
+	orm := PrepareTables(t, registry, entity)
+	plugin.orm = orm.(*ormImplementation)
+	orm.SetFlushRetryPolicy(&FlushRetryPolicy{MaxAttempts: 2, Backoff: time.Millisecond})
+
+	entityA := NewEntity[flushRetryUpsertEntity](orm)
+	entityA.Name = "a"
+	entityA.Age = 10
+	UpsertEntity(orm, entityA)
+	entityB := NewEntity[flushRetryUpsertEntity](orm)
+	entityB.Name = "b"
+	entityB.Age = 10
+	UpsertEntity(orm, entityB)
+
+	plugin.failures = 1
+	loggerRedis := &MockLogHandler{}
+	orm.RegisterQueryLogger(loggerRedis, false, true, false)
+	assert.NoError(t, orm.Flush())
+	assert.Equal(t, 0, plugin.failures)
+	commands := ""
+	for _, log := range loggerRedis.Logs {
+		if log["operation"] == "PIPELINE EXEC" {
+			commands += log["query"].(string)
+		}
+	}
+	assert.Equal(t, 2, strings.Count(commands, "RPUSH "))
+	assert.Equal(t, 2, strings.Count(commands, "HSET "))
+	assert.Equal(t, 2, strings.Count(commands, "SADD "))
+	assert.Equal(t, 2, GetByIndex[flushRetryUpsertEntity](orm, "Age", 10).Len())
+}
@@ -1213,3 +1213,57 @@ func testFlush(orm ORM, async bool) error {
 	}
 	return orm.Flush()
 }
+
+type flushInsertChunksEntity struct {
+	ID   uint64
+	Name string
+}
+
+func TestFlushInsertChunks(t *testing.T) {
+	var entity *flushInsertChunksEntity
+	orm := PrepareTables(t, NewRegistry(), entity)
+	options := orm.Engine().DB(DefaultPoolCode).GetConfig().GetOptions()
+	options.MaxInsertRows = 2
+	defer func() {
+		options.MaxInsertRows = 0
+	}()
+	for i := 0; i < 5; i++ {
+		entity = NewEntity[flushInsertChunksEntity](orm)
+		entity.Name = fmt.Sprintf("name %d", i)
+	}
+	loggerDB := &MockLogHandler{}
+	orm.RegisterQueryLogger(loggerDB, true, false, false)
+	assert.NoError(t, orm.Flush())
+	assert.Len(t, loggerDB.Logs, 5)
+	assert.Equal(t, "START TRANSACTION", loggerDB.Logs[0]["query"])
+	assert.Equal(t, "COMMIT", loggerDB.Logs[4]["query"])
+	total := 0
+	orm.Engine().DB(DefaultPoolCode).QueryRow(orm, NewWhere("SELECT COUNT(*) FROM `flushInsertChunksEntity`"), &total)
+	assert.Equal(t, 5, total)
+
+	plan, err := orm.FlushPlan()
+	assert.NoError(t, err)
+	assert.Len(t, plan.Queries, 0)
+	for i := 0; i < 3; i++ {
+		entity = NewEntity[flushInsertChunksEntity](orm)
+		entity.Name = strings.Repeat("a", 10)
+	}
+	plan, err = orm.FlushPlan()
+	assert.NoError(t, err)
+	assert.Len(t, plan.Queries, 2)
+	assert.Len(t, plan.Queries[0].Parameters, 4)
+	assert.Len(t, plan.Queries[1].Parameters, 2)
+	orm.ClearFlush()
+
+	options.MaxInsertRows = 0
+	for i := 0; i < mySQLMaxPlaceholders/2+1; i++ {
+		entity = NewEntity[flushInsertChunksEntity](orm)
+		entity.Name = "a"
+	}
+	plan, err = orm.FlushPlan()
+	assert.NoError(t, err)
+	assert.Len(t, plan.Queries, 2)
+	assert.Len(t, plan.Queries[0].Parameters, mySQLMaxPlaceholders-1)
+	assert.Len(t, plan.Queries[1].Parameters, 2)
+	orm.ClearFlush()
+}
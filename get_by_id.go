@@ -42,7 +42,16 @@ func getByID(orm *ormImplementation, id uint64, schema *entitySchema) (any, bool
 	var cacheKey string
 	if hasRedis {
 		cacheKey = schema.getCacheKey() + ":" + strconv.FormatUint(id, 10)
-		row := cacheRedis.LRange(orm, cacheKey, 0, int64(len(schema.columnNames)+1))
+		var row []string
+		if schema.redisCacheTTLSliding {
+			p := orm.RedisPipeLine(cacheRedis.GetCode())
+			lRange := p.LRange(cacheKey, 0, int64(len(schema.columnNames)+1))
+			schema.refreshRedisCacheTTL(p, cacheKey)
+			p.Exec(orm)
+			row = lRange.Result()
+		} else {
+			row = cacheRedis.LRange(orm, cacheKey, 0, int64(len(schema.columnNames)+1))
+		}
 		l := len(row)
 		if len(row) > 0 {
 			if l == 1 {
@@ -61,9 +70,10 @@ func getByID(orm *ormImplementation, id uint64, schema *entitySchema) (any, bool
 			}
 		}
 	}
-	query := "SELECT " + schema.fieldsQuery + " FROM `" + schema.GetTableName() + "` WHERE ID = ? LIMIT 1"
+	where := schema.excludeDeleted(NewWhere("ID = ?", id))
+	query := "SELECT " + schema.fieldsQuery + " FROM `" + schema.GetTableName() + "` WHERE " + where.String() + " LIMIT 1"
 	pointers := prepareScan(schema)
-	found := schema.GetDB().QueryRow(orm, NewWhere(query, id), pointers...)
+	found := schema.GetDB().QueryRow(orm, NewWhere(query, where.GetParameters()...), pointers...)
 	if found {
 		value := reflect.New(schema.t)
 		entity := value.Interface()
@@ -76,7 +86,14 @@ func getByID(orm *ormImplementation, id uint64, schema *entitySchema) (any, bool
 			err := fillBindFromOneSource(orm, bind, reflect.ValueOf(entity).Elem(), schema.fields, "")
 			checkError(err)
 			values := convertBindToRedisValue(bind, schema)
-			cacheRedis.RPush(orm, cacheKey, values...)
+			if schema.redisCacheTTL > 0 {
+				p := orm.RedisPipeLine(cacheRedis.GetCode())
+				p.RPush(cacheKey, values...)
+				schema.setRedisCacheTTL(p, cacheKey)
+				p.Exec(orm)
+			} else {
+				cacheRedis.RPush(orm, cacheKey, values...)
+			}
 		}
 		return entity, true
 	}
@@ -87,6 +104,7 @@ func getByID(orm *ormImplementation, id uint64, schema *entitySchema) (any, bool
 		p := orm.RedisPipeLine(cacheRedis.GetCode())
 		p.Del(cacheKey)
 		p.RPush(cacheKey, cacheNilValue)
+		schema.setRedisCacheTTL(p, cacheKey)
 		p.Exec(orm)
 	}
 	return nil, false
@@ -2,8 +2,11 @@ package beeorm
 
 import (
 	"fmt"
+	"strconv"
 	"testing"
+	"time"
 
+	"github.com/redis/go-redis/v9"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -94,3 +97,94 @@ func testGetByID(t *testing.T, local, redis bool) {
 		assert.Len(t, loggerDB.Logs, 0)
 	}
 }
+
+type getByIDTTLEntity struct {
+	ID   uint64 `orm:"redisCache;redisCacheTTL=1m;redisCacheTTLSliding"`
+	Name string `orm:"max=100"`
+	Age  uint8  `orm:"index=Age;cached"`
+}
+
+func TestGetByIdRedisCacheTTL(t *testing.T) {
+	var entity *getByIDTTLEntity
+	orm := PrepareTables(t, NewRegistry(), entity)
+	schema := getEntitySchema[getByIDTTLEntity](orm)
+	r := orm.Engine().Redis(DefaultPoolCode)
+	ttl := func(key string) time.Duration {
+		cmd := redis.NewDurationCmd(orm.Context(), time.Second, "TTL", key)
+		assert.NoError(t, r.Process(orm, cmd))
+		return cmd.Val()
+	}
+
+	entity = NewEntity[getByIDTTLEntity](orm)
+	entity.Name = "a"
+	entity.Age = 10
+	assert.NoError(t, orm.Flush())
+	cacheKey := schema.getCacheKey() + ":" + strconv.FormatUint(entity.ID, 10)
+	assert.Greater(t, ttl(cacheKey), time.Second*50)
+
+	r.Expire(orm, cacheKey, time.Second*10)
+	entity, found := GetByID[getByIDTTLEntity](orm, entity.ID)
+	assert.True(t, found)
+	assert.Greater(t, ttl(cacheKey), time.Second*50)
+	r.Expire(orm, cacheKey, time.Second*10)
+	assert.Equal(t, 1, GetByIDs[getByIDTTLEntity](orm, entity.ID).Len())
+	assert.Greater(t, ttl(cacheKey), time.Second*50)
+
+	r.Del(orm, cacheKey)
+	entity, found = GetByID[getByIDTTLEntity](orm, entity.ID)
+	assert.True(t, found)
+	assert.Greater(t, ttl(cacheKey), time.Second*50)
+
+	_, found = GetByID[getByIDTTLEntity](orm, 1)
+	assert.False(t, found)
+	assert.Greater(t, ttl(schema.getCacheKey()+":1"), time.Second*50)
+
+	assert.Equal(t, 1, GetByIndex[getByIDTTLEntity](orm, "Age", 10).Len())
+	indexKey := schema.cacheKey + ":Age:" + strconv.FormatUint(hashIndexAttributes([]any{uint8(10)}), 10)
+	assert.Greater(t, ttl(indexKey), time.Second*50)
+	r.Expire(orm, indexKey, time.Second*10)
+	loggerRedis := &MockLogHandler{}
+	orm.RegisterQueryLogger(loggerRedis, false, true, false)
+	assert.Equal(t, 1, GetByIndex[getByIDTTLEntity](orm, "Age", 10).Len())
+	assert.NotEmpty(t, loggerRedis.Logs)
+	for _, log := range loggerRedis.Logs {
+		assert.Equal(t, "PIPELINE EXEC", log["operation"])
+	}
+	assert.Greater(t, ttl(indexKey), time.Second*50)
+
+	entity = EditEntity(orm, entity)
+	entity.Name = "b"
+	assert.NoError(t, orm.Flush())
+	assert.Greater(t, ttl(cacheKey), time.Second*50)
+	entity, found = GetByID[getByIDTTLEntity](orm, entity.ID)
+	assert.True(t, found)
+	assert.Equal(t, "b", entity.Name)
+
+	entity = EditEntity(orm, entity)
+	entity.Name = "c"
+	assert.NoError(t, orm.FlushAsync())
+	entity, found = GetByID[getByIDTTLEntity](orm, entity.ID)
+	assert.True(t, found)
+	assert.Equal(t, "c", entity.Name)
+	assert.NoError(t, runAsyncConsumer(orm, false))
+
+	r.Del(orm, cacheKey)
+	entity = EditEntity(orm, entity)
+	entity.Name = "d"
+	assert.NoError(t, orm.Flush())
+	assert.Equal(t, int64(0), r.Exists(orm, cacheKey))
+	entity, found = GetByID[getByIDTTLEntity](orm, entity.ID)
+	assert.True(t, found)
+	assert.Equal(t, "d", entity.Name)
+
+	registry := NewRegistry()
+	registry.RegisterMySQL("root:root@tcp(localhost:3377)/test", DefaultPoolCode, &MySQLOptions{})
+	registry.RegisterRedis("localhost:6385", 0, DefaultPoolCode, nil)
+	registry.RegisterEntity(&getByIDInvalidTTLEntity{})
+	_, err := registry.Validate()
+	assert.EqualError(t, err, "invalid redis cache TTL '10ms'")
+}
+
+type getByIDInvalidTTLEntity struct {
+	ID uint64 `orm:"redisCache;redisCacheTTL=10ms"`
+}
@@ -27,7 +27,9 @@ func getByIDs[E any](orm *ormImplementation, ids []uint64) EntityIterator[E] {
 		l := int64(len(schema.columnNames) + 1)
 		lRanges := make([]*PipeLineSlice, len(ids))
 		for i, id := range ids {
-			lRanges[i] = redisPipeline.LRange(schema.cacheKey+":"+strconv.FormatUint(id, 10), 0, l)
+			cacheKey := schema.cacheKey + ":" + strconv.FormatUint(id, 10)
+			lRanges[i] = redisPipeline.LRange(cacheKey, 0, l)
+			schema.refreshRedisCacheTTL(redisPipeline, cacheKey)
 		}
 		redisPipeline.Exec(orm)
 		for i, id := range ids {
@@ -70,6 +72,9 @@ func getByIDs[E any](orm *ormImplementation, ids []uint64) EntityIterator[E] {
 		toSearch = len(ids)
 	}
 	sql += ")"
+	if schema.softDelete {
+		sql += " AND `" + fakeDeleteColumn + "` = 0"
+	}
 	execRedisPipeline := false
 	res, def := schema.GetDB().Query(orm, sql)
 	defer def()
@@ -94,7 +99,9 @@ func getByIDs[E any](orm *ormImplementation, ids []uint64) EntityIterator[E] {
 			err := fillBindFromOneSource(orm, bind, value.Elem(), schema.fields, "")
 			checkError(err)
 			values := convertBindToRedisValue(bind, schema)
-			redisPipeline.RPush(schema.getCacheKey()+":"+strconv.FormatUint(id, 10), values...)
+			cacheKey := schema.getCacheKey() + ":" + strconv.FormatUint(id, 10)
+			redisPipeline.RPush(cacheKey, values...)
+			schema.setRedisCacheTTL(redisPipeline, cacheKey)
 			execRedisPipeline = true
 		}
 	}
@@ -109,6 +116,7 @@ func getByIDs[E any](orm *ormImplementation, ids []uint64) EntityIterator[E] {
 					cacheKey := schema.getCacheKey() + ":" + strconv.FormatUint(id, 10)
 					redisPipeline.Del(cacheKey)
 					redisPipeline.RPush(cacheKey, cacheNilValue)
+					schema.setRedisCacheTTL(redisPipeline, cacheKey)
 					execRedisPipeline = true
 				}
 			}
@@ -148,7 +156,9 @@ func warmup(orm *ormImplementation, schema *entitySchema, ids []uint64) {
 		l := int64(len(schema.columnNames) + 1)
 		lRanges := make([]*PipeLineSlice, len(missingKeys))
 		for i, index := range missingKeys {
-			lRanges[i] = redisPipeline.LRange(schema.cacheKey+":"+strconv.FormatUint(ids[index], 10), 0, l)
+			cacheKey := schema.cacheKey + ":" + strconv.FormatUint(ids[index], 10)
+			lRanges[i] = redisPipeline.LRange(cacheKey, 0, l)
+			schema.refreshRedisCacheTTL(redisPipeline, cacheKey)
 		}
 		redisPipeline.Exec(orm)
 		hasMissing := false
@@ -186,6 +196,9 @@ func warmup(orm *ormImplementation, schema *entitySchema, ids []uint64) {
 		sql += strconv.FormatUint(ids[key], 10)
 	}
 	sql += ")"
+	if schema.softDelete {
+		sql += " AND `" + fakeDeleteColumn + "` = 0"
+	}
 	execRedisPipeline := false
 	res, def := schema.GetDB().Query(orm, sql)
 	defer def()
@@ -212,7 +225,9 @@ func warmup(orm *ormImplementation, schema *entitySchema, ids []uint64) {
 			err := fillBindFromOneSource(orm, bind, value.Elem(), schema.fields, "")
 			checkError(err)
 			values := convertBindToRedisValue(bind, schema)
-			redisPipeline.RPush(schema.getCacheKey()+":"+strconv.FormatUint(id, 10), values...)
+			cacheKey := schema.getCacheKey() + ":" + strconv.FormatUint(id, 10)
+			redisPipeline.RPush(cacheKey, values...)
+			schema.setRedisCacheTTL(redisPipeline, cacheKey)
 			execRedisPipeline = true
 		}
 	}
@@ -227,6 +242,7 @@ func warmup(orm *ormImplementation, schema *entitySchema, ids []uint64) {
 					cacheKey := schema.getCacheKey() + ":" + strconv.FormatUint(ids[index], 10)
 					redisPipeline.Del(cacheKey)
 					redisPipeline.RPush(cacheKey, cacheNilValue)
+					schema.setRedisCacheTTL(redisPipeline, cacheKey)
 					execRedisPipeline = true
 				}
 			}
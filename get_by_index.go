@@ -88,7 +88,16 @@ func getCachedByColumns[E any](orm ORM, indexName string, index indexDefinition,
 	}
 	rc := orm.Engine().Redis(schema.getForcedRedisCode())
 	redisSetKey := schema.cacheKey + ":" + indexName + ":" + strconv.FormatUint(bindID, 10)
-	fromRedis := rc.SMembers(orm, redisSetKey)
+	var fromRedis []string
+	if schema.redisCacheTTLSliding {
+		p := orm.RedisPipeLine(rc.GetCode())
+		sMembers := p.SMembers(redisSetKey)
+		schema.refreshRedisCacheTTL(p, redisSetKey)
+		p.Exec(orm)
+		fromRedis = sMembers.Result()
+	} else {
+		fromRedis = rc.SMembers(orm, redisSetKey)
+	}
 	if len(fromRedis) > 0 {
 		ids := make([]uint64, len(fromRedis))
 		k := 0
@@ -131,7 +140,10 @@ func getCachedByColumns[E any](orm ORM, indexName string, index indexDefinition,
 		ids := SearchIDs[E](orm, index.CreteWhere(hasNil, attributes), nil)
 		if len(ids) == 0 {
 			schema.localCache.setList(orm, indexName, bindID, cacheNilValue)
-			rc.SAdd(orm, redisSetKey, cacheNilValue)
+			p := orm.RedisPipeLine(rc.GetCode())
+			p.SAdd(redisSetKey, cacheNilValue)
+			schema.setRedisCacheTTL(p, redisSetKey)
+			p.Exec(orm)
 			return &emptyResultsIterator[E]{}
 		}
 		idsForRedis := make([]any, len(ids))
@@ -142,6 +154,7 @@ func getCachedByColumns[E any](orm ORM, indexName string, index indexDefinition,
 		p.Del(redisSetKey)
 		p.SAdd(redisSetKey, redisValidSetValue)
 		p.SAdd(redisSetKey, idsForRedis...)
+		schema.setRedisCacheTTL(p, redisSetKey)
 		p.Exec(orm)
 		values := GetByIDs[E](orm, ids...)
 		if schema.hasLocalCache {
@@ -153,7 +166,10 @@ func getCachedByColumns[E any](orm ORM, indexName string, index indexDefinition,
 	}
 	values := Search[E](orm, index.CreteWhere(hasNil, attributes), nil)
 	if values.Len() == 0 {
-		rc.SAdd(orm, redisSetKey, redisValidSetValue, cacheNilValue)
+		p := orm.RedisPipeLine(rc.GetCode())
+		p.SAdd(redisSetKey, redisValidSetValue, cacheNilValue)
+		schema.setRedisCacheTTL(p, redisSetKey)
+		p.Exec(orm)
 	} else {
 		idsForRedis := make([]any, values.Len()+1)
 		idsForRedis[0] = redisValidSetValue
@@ -163,7 +179,10 @@ func getCachedByColumns[E any](orm ORM, indexName string, index indexDefinition,
 			i++
 		}
 		values.Reset()
-		rc.SAdd(orm, redisSetKey, idsForRedis...)
+		p := orm.RedisPipeLine(rc.GetCode())
+		p.SAdd(redisSetKey, idsForRedis...)
+		schema.setRedisCacheTTL(p, redisSetKey)
+		p.Exec(orm)
 	}
 	return values
 }
@@ -50,7 +50,16 @@ func getCachedByReference[E any](orm ORM, key string, id uint64, schema *entityS
 		idAsString := strconv.FormatUint(id, 10)
 		redisSetKey += ":" + idAsString
 	}
-	fromRedis := rc.SMembers(orm, redisSetKey)
+	var fromRedis []string
+	if schema.redisCacheTTLSliding {
+		p := orm.RedisPipeLine(rc.GetCode())
+		sMembers := p.SMembers(redisSetKey)
+		schema.refreshRedisCacheTTL(p, redisSetKey)
+		p.Exec(orm)
+		fromRedis = sMembers.Result()
+	} else {
+		fromRedis = rc.SMembers(orm, redisSetKey)
+	}
 	if len(fromRedis) > 0 {
 		ids := make([]uint64, len(fromRedis))
 		k := 0
@@ -99,7 +108,10 @@ func getCachedByReference[E any](orm ORM, key string, id uint64, schema *entityS
 		ids := SearchIDs[E](orm, where, nil)
 		if len(ids) == 0 {
 			schema.localCache.setList(orm, key, id, cacheNilValue)
-			rc.SAdd(orm, redisSetKey, cacheNilValue)
+			p := orm.RedisPipeLine(rc.GetCode())
+			p.SAdd(redisSetKey, cacheNilValue)
+			schema.setRedisCacheTTL(p, redisSetKey)
+			p.Exec(orm)
 			return &emptyResultsIterator[E]{}
 		}
 		idsForRedis := make([]any, len(ids))
@@ -110,6 +122,7 @@ func getCachedByReference[E any](orm ORM, key string, id uint64, schema *entityS
 		p.Del(redisSetKey)
 		p.SAdd(redisSetKey, redisValidSetValue)
 		p.SAdd(redisSetKey, idsForRedis...)
+		schema.setRedisCacheTTL(p, redisSetKey)
 		p.Exec(orm)
 		values := GetByIDs[E](orm, ids...)
 		if schema.hasLocalCache {
@@ -127,7 +140,10 @@ func getCachedByReference[E any](orm ORM, key string, id uint64, schema *entityS
 	}
 	values := Search[E](orm, where, nil)
 	if values.Len() == 0 {
-		rc.SAdd(orm, redisSetKey, redisValidSetValue, cacheNilValue)
+		p := orm.RedisPipeLine(rc.GetCode())
+		p.SAdd(redisSetKey, redisValidSetValue, cacheNilValue)
+		schema.setRedisCacheTTL(p, redisSetKey)
+		p.Exec(orm)
 	} else {
 		idsForRedis := make([]any, values.Len()+1)
 		idsForRedis[0] = redisValidSetValue
@@ -137,7 +153,10 @@ func getCachedByReference[E any](orm ORM, key string, id uint64, schema *entityS
 			i++
 		}
 		values.Reset()
-		rc.SAdd(orm, redisSetKey, idsForRedis...)
+		p := orm.RedisPipeLine(rc.GetCode())
+		p.SAdd(redisSetKey, idsForRedis...)
+		schema.setRedisCacheTTL(p, redisSetKey)
+		p.Exec(orm)
 	}
 	return values
 }
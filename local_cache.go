@@ -6,6 +6,7 @@ import (
 	"hash/maphash"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/puzpuzpuz/xsync/v2"
 )
@@ -19,6 +20,7 @@ type LocalCacheConfig interface {
 type localCacheConfig struct {
 	code   string
 	limit  int
+	ttl    time.Duration
 	schema EntitySchema
 }
 
@@ -35,19 +37,56 @@ func (c *localCacheConfig) GetSchema() EntitySchema {
 }
 
 type LocalCacheUsage struct {
-	Type      string
-	Limit     uint64
-	Used      uint64
-	Evictions uint64
+	Type        string
+	Limit       uint64
+	Used        uint64
+	Evictions   uint64
+	Expirations uint64
 }
 
 type localCacheElement struct {
 	value      any
 	lruElement *list.Element
+	expires    int64
+}
+
+type localCacheTTLValue struct {
+	value   any
+	expires int64
+}
+
+func localCacheExpires(ttl time.Duration) int64 {
+	if ttl <= 0 {
+		return 0
+	}
+	return time.Now().Add(ttl).UnixNano()
+}
+
+func localCacheExpired(expires int64) bool {
+	return expires > 0 && time.Now().UnixNano() > expires
+}
+
+func localCacheNoLimitValue(value any, ttl time.Duration) any {
+	if ttl <= 0 {
+		return value
+	}
+	return &localCacheTTLValue{value: value, expires: localCacheExpires(ttl)}
+}
+
+func localCacheUnwrapValue(value any) (any, bool) {
+	ttlValue, hasTTL := value.(*localCacheTTLValue)
+	if !hasTTL {
+		return value, true
+	}
+	if localCacheExpired(ttlValue.expires) {
+		return nil, false
+	}
+	return ttlValue.value, true
 }
 
 type LocalCache interface {
 	Set(orm ORM, key string, value any)
+	SetWithTTL(orm ORM, key string, value any, ttl time.Duration)
 	Remove(orm ORM, key string)
 	GetConfig() LocalCacheConfig
 	Get(orm ORM, key string) (value any, ok bool)
@@ -55,6 +94,7 @@ type LocalCache interface {
 	GetUsage() []LocalCacheUsage
 	getEntity(orm ORM, id uint64) (value any, ok bool)
 	setEntity(orm ORM, id uint64, value any)
+	updateEntity(orm ORM, id uint64, value any)
 	removeEntity(orm ORM, id uint64)
 	getList(orm ORM, key string, id uint64) (value any, ok bool)
 	setList(orm ORM, key string, id uint64, value any)
@@ -76,10 +116,16 @@ type localCache struct {
 	evictions            uint64
 	evictionsEntities    uint64
 	evictionsList        map[string]*uint64
+	expirations          uint64
+	expirationsEntities  uint64
+	expirationsList      map[string]*uint64
 }
 
 func newLocalCache(code string, limit int, schema *entitySchema) *localCache {
 	c := &localCache{config: &localCacheConfig{code: code, limit: limit, schema: schema}}
+	if schema != nil {
+		c.config.ttl = schema.localCacheTTL
+	}
 	if limit > 0 {
 		c.cacheLimit = xsync.NewMapOf[*localCacheElement]()
 		c.cacheLRU = list.New()
@@ -102,6 +148,7 @@ func newLocalCache(code string, limit int, schema *entitySchema) *localCache {
 			c.cacheEntitiesLRU = list.New()
 		}
 		if len(schema.cachedReferences) > 0 || len(schema.cachedIndexes) > 0 || schema.cacheAll {
+			c.expirationsList = make(map[string]*uint64)
 			if limit > 0 {
 				c.cacheListLimit = make(map[string]*xsync.MapOf[uint64, *localCacheElement])
 				c.cacheListLRU = make(map[string]*list.List)
@@ -116,6 +163,7 @@ func newLocalCache(code string, limit int, schema *entitySchema) *localCache {
 				initListCache(limit, c, index)
 			}
 			if schema.cacheAll {
+				c.expirationsList[cacheAllFakeReferenceKey] = new(uint64)
 				if limit > 0 {
 					c.cacheListLimit[cacheAllFakeReferenceKey] = xsync.NewTypedMapOf[uint64, *localCacheElement](func(seed maphash.Seed, u uint64) uint64 {
 						return u
@@ -136,6 +184,7 @@ func newLocalCache(code string, limit int, schema *entitySchema) *localCache {
 }
 
 func initListCache(limit int, c *localCache, reference string) {
+	c.expirationsList[reference] = new(uint64)
 	if limit > 0 {
 		c.cacheListLimit[reference] = xsync.NewTypedMapOf[uint64, *localCacheElement](func(seed maphash.Seed, u uint64) uint64 {
 			return u
@@ -157,6 +206,13 @@ func (lc *localCache) GetConfig() LocalCacheConfig {
 func (lc *localCache) Get(orm ORM, key string) (value any, ok bool) {
 	if lc.config.limit > 0 {
 		val, has := lc.cacheLimit.Load(key)
+		if has && localCacheExpired(val.expires) {
+			if _, loaded := lc.cacheLimit.LoadAndDelete(key); loaded {
+				lc.cacheLRU.Remove(val.lruElement)
+				atomic.AddUint64(&lc.expirations, 1)
+			}
+			has = false
+		}
 		hasLog, _ := orm.getLocalCacheLoggers()
 		if hasLog {
 			lc.fillLogFields(orm, "GET", fmt.Sprintf("GET %v", key), !has)
@@ -170,6 +226,13 @@ func (lc *localCache) Get(orm ORM, key string) (value any, ok bool) {
 		return nil, false
 	}
 	value, ok = lc.cacheNoLimit.Load(key)
+	if ok {
+		value, ok = localCacheUnwrapValue(value)
+		if !ok {
+			lc.cacheNoLimit.Delete(key)
+			atomic.AddUint64(&lc.expirations, 1)
+		}
+	}
 	hasLog, _ := orm.getLocalCacheLoggers()
 	if hasLog {
 		lc.fillLogFields(orm, "GET", fmt.Sprintf("GET %v", key), !ok)
@@ -180,6 +243,13 @@ func (lc *localCache) Get(orm ORM, key string) (value any, ok bool) {
 func (lc *localCache) getEntity(orm ORM, id uint64) (value any, ok bool) {
 	if lc.config.limit > 0 {
 		val, has := lc.cacheEntitiesLimit.Load(id)
+		if has && localCacheExpired(val.expires) {
+			if _, loaded := lc.cacheEntitiesLimit.LoadAndDelete(id); loaded {
+				lc.cacheEntitiesLRU.Remove(val.lruElement)
+				atomic.AddUint64(&lc.expirationsEntities, 1)
+			}
+			has = false
+		}
 		hasLog, _ := orm.getLocalCacheLoggers()
 		if hasLog {
 			lc.fillLogFields(orm, "GET", fmt.Sprintf("GET ENTITY %d", id), !has)
@@ -193,6 +263,13 @@ func (lc *localCache) getEntity(orm ORM, id uint64) (value any, ok bool) {
 		return nil, false
 	}
 	value, ok = lc.cacheEntitiesNoLimit.Load(id)
+	if ok {
+		value, ok = localCacheUnwrapValue(value)
+		if !ok {
+			lc.cacheEntitiesNoLimit.Delete(id)
+			atomic.AddUint64(&lc.expirationsEntities, 1)
+		}
+	}
 	hasLog, _ := orm.getLocalCacheLoggers()
 	if hasLog {
 		lc.fillLogFields(orm, "GET", fmt.Sprintf("GET ENTITY %d", id), !ok)
@@ -204,6 +281,13 @@ func (lc *localCache) getList(orm ORM, key string, id uint64) (value any, ok boo
 	if lc.config.limit > 0 {
 		c := lc.cacheListLimit[key]
 		val, has := c.Load(id)
+		if has && localCacheExpired(val.expires) {
+			if _, loaded := c.LoadAndDelete(id); loaded {
+				lc.cacheListLRU[key].Remove(val.lruElement)
+				atomic.AddUint64(lc.expirationsList[key], 1)
+			}
+			has = false
+		}
 		hasLog, _ := orm.getLocalCacheLoggers()
 		if hasLog {
 			lc.fillLogFields(orm, "GET", fmt.Sprintf("GET LIST %s %d", key, id), !has)
@@ -217,6 +301,13 @@ func (lc *localCache) getList(orm ORM, key string, id uint64) (value any, ok boo
 		return nil, false
 	}
 	value, ok = lc.cacheListNoLimit[key].Load(id)
+	if ok {
+		value, ok = localCacheUnwrapValue(value)
+		if !ok {
+			lc.cacheListNoLimit[key].Delete(id)
+			atomic.AddUint64(lc.expirationsList[key], 1)
+		}
+	}
 	hasLog, _ := orm.getLocalCacheLoggers()
 	if hasLog {
 		lc.fillLogFields(orm, "GET", fmt.Sprintf("GET LIST %s %d", key, id), !ok)
@@ -225,9 +316,13 @@ func (lc *localCache) getList(orm ORM, key string, id uint64) (value any, ok boo
 }
 
 func (lc *localCache) Set(orm ORM, key string, value any) {
+	lc.SetWithTTL(orm, key, value, 0)
+}
+
+func (lc *localCache) SetWithTTL(orm ORM, key string, value any, ttl time.Duration) {
 	if lc.config.limit > 0 {
 		element := lc.cacheLRU.PushFront(key)
-		lc.cacheLimit.Store(key, &localCacheElement{lruElement: element, value: value})
+		lc.cacheLimit.Store(key, &localCacheElement{lruElement: element, value: value, expires: localCacheExpires(ttl)})
 		if lc.cacheLimit.Size() > lc.config.limit {
 			toRemove := lc.cacheLRU.Back()
 			if toRemove != nil {
@@ -241,7 +336,7 @@ func (lc *localCache) Set(orm ORM, key string, value any) {
 		}
 		return
 	}
-	lc.cacheNoLimit.Store(key, value)
+	lc.cacheNoLimit.Store(key, localCacheNoLimitValue(value, ttl))
 	hasLog, _ := orm.getLocalCacheLoggers()
 	if hasLog {
 		lc.fillLogFields(orm, "SET", fmt.Sprintf("SET %s %v", key, value), false)
@@ -249,9 +344,12 @@ func (lc *localCache) Set(orm ORM, key string, value any) {
 }
 
 func (lc *localCache) setEntity(orm ORM, id uint64, value any) {
+	if lc.addToFlushPlan(orm, "SET ENTITY", "", id) {
+		return
+	}
 	if lc.config.limit > 0 {
 		element := lc.cacheEntitiesLRU.PushFront(id)
-		lc.cacheEntitiesLimit.Store(id, &localCacheElement{lruElement: element, value: value})
+		lc.cacheEntitiesLimit.Store(id, &localCacheElement{lruElement: element, value: value, expires: localCacheExpires(lc.config.ttl)})
 		if lc.cacheEntitiesLimit.Size() > lc.config.limit {
 			toRemove := lc.cacheEntitiesLRU.Back()
 			if toRemove != nil {
@@ -265,7 +363,7 @@ func (lc *localCache) setEntity(orm ORM, id uint64, value any) {
 		}
 		return
 	}
-	lc.cacheEntitiesNoLimit.Store(id, value)
+	lc.cacheEntitiesNoLimit.Store(id, localCacheNoLimitValue(value, lc.config.ttl))
 	hasLog, _ := orm.getLocalCacheLoggers()
 	if hasLog {
 		lc.fillLogFields(orm, "SET", fmt.Sprintf("SET ENTITY %d [entity value]", id), false)
@@ -273,10 +371,13 @@ func (lc *localCache) setEntity(orm ORM, id uint64, value any) {
 }
 
 func (lc *localCache) setList(orm ORM, key string, id uint64, value any) {
+	if lc.addToFlushPlan(orm, "SET LIST", key, id) {
+		return
+	}
 	if lc.config.limit > 0 {
 		element := lc.cacheEntitiesLRU.PushFront(id)
 		c := lc.cacheListLimit[key]
-		c.Store(id, &localCacheElement{lruElement: element, value: value})
+		c.Store(id, &localCacheElement{lruElement: element, value: value, expires: localCacheExpires(lc.config.ttl)})
 		lru := lc.cacheListLRU[key]
 		if c.Size() > lc.config.limit {
 			toRemove := lru.Back()
@@ -291,7 +392,7 @@ func (lc *localCache) setList(orm ORM, key string, id uint64, value any) {
 		}
 		return
 	}
-	lc.cacheListNoLimit[key].Store(id, value)
+	lc.cacheListNoLimit[key].Store(id, localCacheNoLimitValue(value, lc.config.ttl))
 	hasLog, _ := orm.getLocalCacheLoggers()
 	if hasLog {
 		lc.fillLogFields(orm, "SET", fmt.Sprintf("SET LIST %s %d %v", key, id, value), false)
@@ -314,6 +415,14 @@ func (lc *localCache) Remove(orm ORM, key string) {
 }
 
 func (lc *localCache) removeEntity(orm ORM, id uint64) {
+	if lc.addToFlushPlan(orm, "REMOVE ENTITY", "", id) {
+		return
+	}
+	lc.deleteEntity(orm, id)
+	lc.publishInvalidation(orm, "", id)
+}
+
+func (lc *localCache) deleteEntity(orm ORM, id uint64) {
 	if lc.config.limit > 0 {
 		val, loaded := lc.cacheEntitiesLimit.LoadAndDelete(id)
 		if loaded {
@@ -329,13 +438,29 @@ func (lc *localCache) removeEntity(orm ORM, id uint64) {
 }
 
 func (lc *localCache) removeList(orm ORM, key string, id uint64) {
+	if lc.addToFlushPlan(orm, "REMOVE LIST", key, id) {
+		return
+	}
+	lc.deleteList(orm, key, id)
+	lc.publishInvalidation(orm, key, id)
+}
+
+func (lc *localCache) deleteList(orm ORM, key string, id uint64) {
 	if lc.config.limit > 0 {
-		val, loaded := lc.cacheListLimit[key].LoadAndDelete(id)
+		c, has := lc.cacheListLimit[key]
+		if !has {
+			return
+		}
+		val, loaded := c.LoadAndDelete(id)
 		if loaded {
 			lc.cacheListLRU[key].Remove(val.lruElement)
 		}
 	} else {
-		lc.cacheListNoLimit[key].Delete(id)
+		c, has := lc.cacheListNoLimit[key]
+		if !has {
+			return
+		}
+		c.Delete(id)
 	}
 	hasLog, _ := orm.getLocalCacheLoggers()
 	if hasLog {
@@ -379,25 +504,25 @@ func (lc *localCache) Clear(orm ORM) {
 func (lc *localCache) GetUsage() []LocalCacheUsage {
 	if lc.config.limit > 0 {
 		if lc.cacheEntitiesLimit == nil {
-			return []LocalCacheUsage{{Type: "Global", Used: uint64(lc.cacheLimit.Size()), Limit: uint64(lc.config.limit), Evictions: lc.evictions}}
+			return []LocalCacheUsage{{Type: "Global", Used: uint64(lc.cacheLimit.Size()), Limit: uint64(lc.config.limit), Evictions: lc.evictions, Expirations: lc.expirations}}
 		}
 		usage := make([]LocalCacheUsage, len(lc.cacheListLimit)+1)
-		usage[0] = LocalCacheUsage{Type: "Entities " + lc.config.schema.GetType().String(), Used: uint64(lc.cacheEntitiesLimit.Size()), Limit: uint64(lc.config.limit), Evictions: lc.evictionsEntities}
+		usage[0] = LocalCacheUsage{Type: "Entities " + lc.config.schema.GetType().String(), Used: uint64(lc.cacheEntitiesLimit.Size()), Limit: uint64(lc.config.limit), Evictions: lc.evictionsEntities, Expirations: lc.expirationsEntities}
 		i := 1
 		for key, l := range lc.cacheListLimit {
-			usage[i] = LocalCacheUsage{Type: "List " + key + " of " + lc.config.schema.GetType().String(), Used: uint64(l.Size()), Limit: uint64(lc.config.limit), Evictions: *lc.evictionsList[key]}
+			usage[i] = LocalCacheUsage{Type: "List " + key + " of " + lc.config.schema.GetType().String(), Used: uint64(l.Size()), Limit: uint64(lc.config.limit), Evictions: *lc.evictionsList[key], Expirations: *lc.expirationsList[key]}
 			i++
 		}
 		return usage
 	}
 	if lc.cacheEntitiesNoLimit == nil {
-		return []LocalCacheUsage{{Type: "Global", Used: uint64(lc.cacheNoLimit.Size()), Limit: 0, Evictions: 0}}
+		return []LocalCacheUsage{{Type: "Global", Used: uint64(lc.cacheNoLimit.Size()), Limit: 0, Evictions: 0, Expirations: lc.expirations}}
 	}
 	usage := make([]LocalCacheUsage, len(lc.cacheListNoLimit)+1)
-	usage[0] = LocalCacheUsage{Type: "Entities " + lc.config.schema.GetType().String(), Used: uint64(lc.cacheEntitiesNoLimit.Size()), Limit: 0, Evictions: 0}
+	usage[0] = LocalCacheUsage{Type: "Entities " + lc.config.schema.GetType().String(), Used: uint64(lc.cacheEntitiesNoLimit.Size()), Limit: 0, Evictions: 0, Expirations: lc.expirationsEntities}
 	i := 1
 	for refName, l := range lc.cacheListNoLimit {
-		usage[i] = LocalCacheUsage{Type: "List " + refName + " of " + lc.config.schema.GetType().String(), Used: uint64(l.Size()), Limit: 0, Evictions: 0}
+		usage[i] = LocalCacheUsage{Type: "List " + refName + " of " + lc.config.schema.GetType().String(), Used: uint64(l.Size()), Limit: 0, Evictions: 0, Expirations: *lc.expirationsList[refName]}
 		i++
 	}
 	return usage
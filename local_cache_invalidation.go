@@ -0,0 +1,228 @@
+package beeorm
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/redis/go-redis/v9"
+)
+
+const localCacheInvalidationChannel = "beeorm:local_cache"
+const localCacheInvalidationBatchSize = 100
+const localCacheInvalidationInterval = time.Millisecond * 10
+const localCacheInvalidationQueueSize = 10000
+const localCacheInvalidationReconnectDelay = time.Second
+
+type LocalCacheInvalidationOptions struct {
+	RedisPool     string
+	Channel       string
+	BatchSize     int
+	BatchInterval time.Duration
+	QueueSize     int
+}
+
+type localCacheInvalidation struct {
+	Schema string `json:"s"`
+	List   string `json:"l,omitempty"`
+	ID     uint64 `json:"i"`
+}
+
+type localCacheInvalidationMessage struct {
+	Sender string                   `json:"p"`
+	Items  []localCacheInvalidation `json:"e,omitempty"`
+	Resync bool                     `json:"r,omitempty"`
+}
+
+type localCacheInvalidationBus struct {
+	options       *LocalCacheInvalidationOptions
+	sender        string
+	queue         chan localCacheInvalidation
+	publisherOnce sync.Once
+	running       atomic.Bool
+	overflow      atomic.Bool
+	errF          atomic.Pointer[func(err error)]
+}
+
+func newLocalCacheInvalidationBus(options *LocalCacheInvalidationOptions) *localCacheInvalidationBus {
+	hostname, _ := os.Hostname()
+	return &localCacheInvalidationBus{
+		options: options,
+		sender:  fmt.Sprintf("%s:%d:%d", hostname, os.Getpid(), time.Now().UnixNano()),
+		queue:   make(chan localCacheInvalidation, options.QueueSize),
+	}
+}
+
+// add queues invalidation for the publisher that is started with the first one, so every flushing process
+// publishes its changes even if it doesn't consume invalidations from other processes
+func (b *localCacheInvalidationBus) add(orm ORM, item localCacheInvalidation) {
+	b.publisherOnce.Do(func() {
+		r := orm.Engine().Redis(b.options.RedisPool)
+		go b.publish(orm.CloneWithContext(context.Background()), r)
+	})
+	select {
+	case b.queue <- item:
+	default:
+		b.overflow.Store(true)
+	}
+}
+
+func (lc *localCache) publishInvalidation(orm ORM, list string, id uint64) {
+	if orm.(*ormImplementation).plan != nil || lc.config.schema == nil {
+		return
+	}
+	bus := orm.Engine().(*engineImplementation).localCacheInvalidation
+	if bus == nil {
+		return
+	}
+	bus.add(orm, localCacheInvalidation{Schema: lc.config.schema.GetType().String(), List: list, ID: id})
+}
+
+func (lc *localCache) updateEntity(orm ORM, id uint64, value any) {
+	lc.setEntity(orm, id, value)
+	lc.publishInvalidation(orm, "", id)
+}
+
+func ConsumeLocalCacheInvalidations(orm ORM, errF func(err error)) (stop func()) {
+	engine := orm.Engine().(*engineImplementation)
+	bus := engine.localCacheInvalidation
+	if bus == nil {
+		panic("local cache invalidation is not enabled")
+	}
+	if !bus.running.CompareAndSwap(false, true) {
+		panic("consumer is already running")
+	}
+	if errF != nil {
+		bus.errF.Store(&errF)
+	}
+	r := engine.Redis(bus.options.RedisPool).(*redisCache)
+	ctx, cancel := context.WithCancel(context.Background())
+	pubSub := r.client.Subscribe(ctx, bus.options.Channel)
+	waitGroup := &sync.WaitGroup{}
+	waitGroup.Add(1)
+	go func() {
+		defer waitGroup.Done()
+		bus.subscribe(ctx, orm.CloneWithContext(context.Background()), pubSub, errF)
+	}()
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			cancel()
+			_ = pubSub.Close()
+			waitGroup.Wait()
+			bus.errF.Store(nil)
+			bus.running.Store(false)
+		})
+	}
+}
+
+func (b *localCacheInvalidationBus) publish(orm ORM, r RedisCache) {
+	ticker := time.NewTicker(b.options.BatchInterval)
+	defer ticker.Stop()
+	batch := make([]localCacheInvalidation, 0, b.options.BatchSize)
+	for {
+		select {
+		case item := <-b.queue:
+			batch = append(batch, item)
+			if len(batch) < b.options.BatchSize {
+				continue
+			}
+		case <-ticker.C:
+		}
+		batch = b.send(orm, r, batch)
+	}
+}
+
+func (b *localCacheInvalidationBus) send(orm ORM, r RedisCache, batch []localCacheInvalidation) []localCacheInvalidation {
+	message := localCacheInvalidationMessage{Sender: b.sender, Items: batch}
+	if b.overflow.Swap(false) {
+		message.Items = nil
+		message.Resync = true
+	} else if len(batch) == 0 {
+		return batch
+	}
+	defer func() {
+		if rec := recover(); rec != nil {
+			b.overflow.Store(true)
+			if errF := b.errF.Load(); errF != nil {
+				asError, isError := rec.(error)
+				if !isError {
+					asError = fmt.Errorf("%v", rec)
+				}
+				(*errF)(asError)
+			}
+		}
+	}()
+	asJSON, _ := jsoniter.ConfigFastest.MarshalToString(message)
+	r.Publish(orm, b.options.Channel, asJSON)
+	return batch[0:0]
+}
+
+func (b *localCacheInvalidationBus) subscribe(ctx context.Context, orm ORM, pubSub *redis.PubSub, errF func(err error)) {
+	subscribed := false
+	for {
+		received, err := pubSub.Receive(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			if errF != nil {
+				errF(err)
+			}
+			time.Sleep(localCacheInvalidationReconnectDelay)
+			continue
+		}
+		switch message := received.(type) {
+		case *redis.Subscription:
+			if message.Kind != "subscribe" {
+				continue
+			}
+			if subscribed {
+				resyncLocalCaches(orm)
+			}
+			subscribed = true
+		case *redis.Message:
+			b.handleMessage(orm, message.Payload)
+		}
+	}
+}
+
+func (b *localCacheInvalidationBus) handleMessage(orm ORM, payload string) {
+	message := &localCacheInvalidationMessage{}
+	if jsoniter.ConfigFastest.UnmarshalFromString(payload, message) != nil || message.Sender == b.sender {
+		return
+	}
+	if message.Resync {
+		resyncLocalCaches(orm)
+		return
+	}
+	registry := orm.Engine().Registry()
+	for _, item := range message.Items {
+		schema := registry.EntitySchema(item.Schema)
+		if schema == nil {
+			continue
+		}
+		lc, hasLocalCache := schema.GetLocalCache()
+		if !hasLocalCache {
+			continue
+		}
+		if item.List == "" {
+			lc.(*localCache).deleteEntity(orm, item.ID)
+		} else {
+			lc.(*localCache).deleteList(orm, item.List, item.ID)
+		}
+	}
+}
+
+func resyncLocalCaches(orm ORM) {
+	for _, schema := range orm.Engine().Registry().Entities() {
+		lc, hasLocalCache := schema.GetLocalCache()
+		if hasLocalCache {
+			lc.Clear(orm)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package beeorm
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type localCacheInvalidationEntity struct {
+	ID   uint64 `orm:"localCache;redisCache"`
+	Name string `orm:"index=Name"`
+	Age  uint8  `orm:"index=Age;cached"`
+}
+
+func TestLocalCacheInvalidation(t *testing.T) {
+	registry := NewRegistry()
+	registry.SetLocalCacheInvalidation(&LocalCacheInvalidationOptions{BatchInterval: time.Millisecond})
+	var entity *localCacheInvalidationEntity
+	orm := PrepareTables(t, registry, entity)
+
+	registry = NewRegistry()
+	registry.SetLocalCacheInvalidation(&LocalCacheInvalidationOptions{BatchInterval: time.Millisecond})
+	registry.RegisterMySQL("root:root@tcp(localhost:3377)/test", DefaultPoolCode, &MySQLOptions{})
+	registry.RegisterRedis("localhost:6385", 0, DefaultPoolCode, nil)
+	registry.RegisterLocalCache(DefaultPoolCode, 0)
+	registry.RegisterEntity(entity)
+	engine, err := registry.Validate()
+	assert.NoError(t, err)
+	orm2 := engine.NewORM(context.Background())
+
+	// orm only flushes, like API processes that don't consume invalidations
+	stop2 := ConsumeLocalCacheInvalidations(orm2, nil)
+	defer stop2()
+	time.Sleep(time.Millisecond * 50)
+
+	entity = NewEntity[localCacheInvalidationEntity](orm)
+	entity.Name = "a"
+	entity.Age = 10
+	assert.NoError(t, orm.Flush())
+
+	entity2, found := GetByID[localCacheInvalidationEntity](orm2, entity.ID)
+	assert.True(t, found)
+	assert.Equal(t, "a", entity2.Name)
+	assert.Equal(t, 1, GetByIndex[localCacheInvalidationEntity](orm2, "Age", 10).Len())
+	lc2, _ := getEntitySchema[localCacheInvalidationEntity](orm2).GetLocalCache()
+	_, has := lc2.getEntity(orm2, entity.ID)
+	assert.True(t, has)
+
+	entity = EditEntity(orm, entity)
+	entity.Name = "b"
+	entity.Age = 11
+	assert.NoError(t, orm.Flush())
+	assert.Eventually(t, func() bool {
+		_, has = lc2.getEntity(orm2, entity.ID)
+		return !has
+	}, time.Second, time.Millisecond*5)
+	entity2, found = GetByID[localCacheInvalidationEntity](orm2, entity.ID)
+	assert.True(t, found)
+	assert.Equal(t, "b", entity2.Name)
+	assert.Equal(t, 0, GetByIndex[localCacheInvalidationEntity](orm2, "Age", 10).Len())
+	assert.Equal(t, 1, GetByIndex[localCacheInvalidationEntity](orm2, "Age", 11).Len())
+
+	lc, _ := getEntitySchema[localCacheInvalidationEntity](orm).GetLocalCache()
+	_, has = lc.getEntity(orm, entity.ID)
+	assert.True(t, has)
+
+	bus := engine.(*engineImplementation).localCacheInvalidation
+	bus.handleMessage(orm2, `{"p":"other","r":true}`)
+	_, has = lc2.getEntity(orm2, entity.ID)
+	assert.False(t, has)
+
+	registry = NewRegistry()
+	registry.SetLocalCacheInvalidation(&LocalCacheInvalidationOptions{RedisPool: "missing"})
+	_, err = registry.Validate()
+	assert.EqualError(t, err, "local cache invalidation redis pool 'missing' not found")
+}
@@ -3,6 +3,7 @@ package beeorm
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -57,3 +58,67 @@ func TestLocalCache(t *testing.T) {
 	assert.True(t, has)
 	assert.Equal(t, "hello", val)
 }
+
+type localCacheTTLEntity struct {
+	ID   uint64 `orm:"localCache;localCacheTTL=100ms;redisCache"`
+	Name string
+}
+
+func TestLocalCacheTTL(t *testing.T) {
+	registry := NewRegistry()
+	registry.RegisterLocalCache("with_limit", 3)
+	var entity *localCacheTTLEntity
+	orm := PrepareTables(t, registry, entity)
+
+	for _, code := range []string{DefaultPoolCode, "with_limit"} {
+		lc := orm.Engine().LocalCache(code)
+		lc.SetWithTTL(orm, "ttl", "hello", time.Millisecond*100)
+		lc.Set(orm, "no_ttl", "hello")
+		val, has := lc.Get(orm, "ttl")
+		assert.True(t, has)
+		assert.Equal(t, "hello", val)
+		time.Sleep(time.Millisecond * 150)
+		_, has = lc.Get(orm, "ttl")
+		assert.False(t, has)
+		_, has = lc.Get(orm, "no_ttl")
+		assert.True(t, has)
+		assert.Equal(t, uint64(1), lc.GetUsage()[0].Expirations)
+		assert.Equal(t, uint64(0), lc.GetUsage()[0].Evictions)
+	}
+
+	entity = NewEntity[localCacheTTLEntity](orm)
+	entity.Name = "a"
+	assert.NoError(t, orm.Flush())
+	schema := getEntitySchema[localCacheTTLEntity](orm)
+	lc, _ := schema.GetLocalCache()
+	entity, found := GetByID[localCacheTTLEntity](orm, entity.ID)
+	assert.True(t, found)
+	_, has := lc.getEntity(orm, entity.ID)
+	assert.True(t, has)
+	time.Sleep(time.Millisecond * 150)
+	_, has = lc.getEntity(orm, entity.ID)
+	assert.False(t, has)
+	assert.Equal(t, uint64(1), lc.GetUsage()[0].Expirations)
+
+	loggerRedis := &MockLogHandler{}
+	orm.RegisterQueryLogger(loggerRedis, false, true, false)
+	entity, found = GetByID[localCacheTTLEntity](orm, entity.ID)
+	assert.True(t, found)
+	assert.Equal(t, "a", entity.Name)
+	assert.Len(t, loggerRedis.Logs, 1)
+	loggerRedis.Clear()
+	entity, found = GetByID[localCacheTTLEntity](orm, entity.ID)
+	assert.True(t, found)
+	assert.Len(t, loggerRedis.Logs, 0)
+
+	registry = NewRegistry()
+	registry.RegisterMySQL("root:root@tcp(localhost:3377)/test", DefaultPoolCode, &MySQLOptions{})
+	registry.RegisterLocalCache(DefaultPoolCode, 0)
+	registry.RegisterEntity(&localCacheInvalidTTLEntity{})
+	_, err := registry.Validate()
+	assert.EqualError(t, err, "invalid local cache TTL 'abc'")
+}
+
+type localCacheInvalidTTLEntity struct {
+	ID uint64 `orm:"localCache;localCacheTTL=abc"`
+}
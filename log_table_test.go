@@ -154,3 +154,20 @@ func TestLogTable(t *testing.T) {
 	assert.Len(t, bind, 1)
 	assert.Equal(t, float64(42), bind["Age"])
 }
+
+func TestLogTableAsyncEvent(t *testing.T) {
+	var entity *logTableEntity
+	orm := PrepareTables(t, NewRegistry(), entity, LogEntity[logTableEntity]{})
+	schema := getEntitySchema[logTableEntity](orm)
+	logSchema := orm.Engine().Registry().EntitySchema(LogEntity[logTableEntity]{})
+
+	event := orm.(*ormImplementation).newLogTableEvent(schema, logSchema.(*entitySchema), 10, nil, Bind{"Name": "a"})
+	_, isStructured := event.structured()
+	assert.True(t, isStructured)
+	decoded, decodedSchema, err := decodeAsyncEvent(orm, event.marshal())
+	assert.NoError(t, err)
+	assert.Equal(t, logSchema, decodedSchema)
+	assert.Equal(t, "10", decoded.After["EntityID"])
+	assert.Equal(t, `{"Name":"a"}`, decoded.After["After"])
+	assert.NotContains(t, decoded.After, "Before")
+}
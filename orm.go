@@ -26,7 +26,10 @@ type ORM interface {
 	Engine() Engine
 	Flush() error
 	FlushAsync() error
+	FlushPlan() (FlushPlan, error)
 	ClearFlush()
+	SetFlushRetryPolicy(policy *FlushRetryPolicy)
+	Transaction(f func(tx ORM) error) error
 	RedisPipeLine(pool string) *RedisPipeLine
 	RegisterQueryLogger(handler LogHandler, mysql, redis, local bool)
 	EnableQueryDebug()
@@ -55,6 +58,15 @@ type ormImplementation struct {
 	redisPipeLines         map[string]*RedisPipeLine
 	flushDBActions         map[string][]dbAction
 	flushPostActions       []func(orm ORM)
+	flushDBCommitActions   []func()
+	asyncEventsToReserve   map[*entitySchema]int64
+	asyncBufferReserved    map[*entitySchema]int64
+	inTransaction          bool
+	plan                   *FlushPlan
+	flushRetryPolicy       *FlushRetryPolicy
+	dbTransactions         map[string]DBTransaction
+	transactionPipeLines   []*RedisPipeLine
+	transactionPostActions []func(orm ORM)
 	mutexFlush             sync.Mutex
 	mutexData              sync.Mutex
 }
@@ -74,6 +86,7 @@ func (orm *ormImplementation) CloneWithContext(context context.Context) ORM {
 		hasDBLogger:            orm.hasDBLogger,
 		hasLocalCacheLogger:    orm.hasLocalCacheLogger,
 		meta:                   orm.meta,
+		flushRetryPolicy:       orm.flushRetryPolicy,
 	}
 }
 
@@ -93,12 +106,16 @@ func (orm *ormImplementation) RedisPipeLine(pool string) *RedisPipeLine {
 	if orm.redisPipeLines == nil {
 		orm.redisPipeLines = make(map[string]*RedisPipeLine)
 	}
-	r := orm.engine.Redis(pool).(*redisCache)
-	pipeline := &RedisPipeLine{orm: orm, pool: pool, r: r, pipeLine: r.client.Pipeline()}
+	pipeline := newRedisPipeLine(orm, pool)
 	orm.redisPipeLines[pool] = pipeline
 	return pipeline
 }
 
+func newRedisPipeLine(orm *ormImplementation, pool string) *RedisPipeLine {
+	r := orm.engine.Redis(pool).(*redisCache)
+	return &RedisPipeLine{orm: orm, pool: pool, r: r, pipeLine: r.client.Pipeline()}
+}
+
 func (orm *ormImplementation) SetMetaData(key, value string) {
 	orm.mutexData.Lock()
 	defer orm.mutexData.Unlock()
@@ -141,6 +158,10 @@ func (orm *ormImplementation) getLocalCacheLoggers() (bool, []LogHandler) {
 func (orm *ormImplementation) trackEntity(e EntityFlush) {
 	orm.mutexFlush.Lock()
 	defer orm.mutexFlush.Unlock()
+	orm.storeTrackedEntity(e)
+}
+
+func (orm *ormImplementation) storeTrackedEntity(e EntityFlush) {
 	if orm.trackedEntities == nil {
 		orm.trackedEntities = xsync.NewTypedMapOf[uint64, *xsync.MapOf[uint64, EntityFlush]](func(seed maphash.Seed, u uint64) uint64 {
 			return u
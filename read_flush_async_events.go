@@ -1,11 +1,17 @@
 package beeorm
 
 import (
+	"errors"
+	"fmt"
 	"slices"
 
 	jsoniter "github.com/json-iterator/go"
 )
 
+const asyncFlushErrorsPage = 1000
+
+var ErrAsyncFlushErrorsChanged = errors.New("async flush errors list is changing too often, try again")
+
 type AsyncFlushEvents interface {
 	EntitySchemas() []EntitySchema
 	EventsCount() uint64
@@ -14,6 +20,11 @@ type AsyncFlushEvents interface {
 	Errors(total int, last bool) []FlushEventWithError
 	TrimEvents(total int)
 	TrimErrors(total int)
+	Retry(total int) (int, error)
+	RetryOne(index int) (bool, error)
+	Discard(filter func(event FlushEventWithError) bool) (int, error)
+	EditAndRetry(index int, newSQL string, params ...any) (bool, error)
+	Stats() *AsyncFlushStats
 	RedilPool() string
 	RedisList() string
 }
@@ -49,7 +60,23 @@ func (s *asyncFlushEvents) RedisList() string {
 
 func (s *asyncFlushEvents) EventsCount() uint64 {
 	r := s.orm.Engine().Redis(s.redisPoolName)
-	return uint64(r.LLen(s.orm, s.listName))
+	total := uint64(r.LLen(s.orm, s.listName))
+	for _, stream := range s.streams() {
+		total += uint64(r.XLen(s.orm, stream))
+	}
+	return total
+}
+
+func (s *asyncFlushEvents) streams() []string {
+	options := s.orm.Engine().(*engineImplementation).asyncFlushStreams
+	if options == nil {
+		return nil
+	}
+	streams := make([]string, options.Partitions)
+	for i := range streams {
+		streams[i] = asyncStreamName(s.listName, i)
+	}
+	return streams
 }
 
 func (s *asyncFlushEvents) ErrorsCount() uint64 {
@@ -60,16 +87,18 @@ func (s *asyncFlushEvents) ErrorsCount() uint64 {
 func (s *asyncFlushEvents) Events(total int) []FlushEvent {
 	r := s.orm.Engine().Redis(s.redisPoolName)
 	events := r.LRange(s.orm, s.listName, 0, int64(total-1))
+	for _, stream := range s.streams() {
+		if len(events) >= total {
+			break
+		}
+		for _, message := range r.XRange(s.orm, stream, "-", "+", int64(total-len(events))) {
+			value, _ := message.Values[asyncStreamsEventField].(string)
+			events = append(events, value)
+		}
+	}
 	results := make([]FlushEvent, len(events))
 	for i, event := range events {
-		var data []string
-		_ = jsoniter.ConfigFastest.UnmarshalFromString(event, &data)
-		if len(data) > 0 {
-			results[i].SQL = data[0]
-			if len(data) > 1 {
-				results[i].QueryAttributes = data[1:]
-			}
-		}
+		results[i] = decodeFlushEvent(s.orm, event)
 	}
 	return results
 }
@@ -87,14 +116,7 @@ func (s *asyncFlushEvents) Errors(total int, last bool) []FlushEventWithError {
 	k := 0
 	for i, event := range events {
 		if i%2 == 0 {
-			var data []string
-			_ = jsoniter.ConfigFastest.UnmarshalFromString(event, &data)
-			if len(data) > 0 {
-				results[k].SQL = data[0]
-				if len(data) > 1 {
-					results[k].QueryAttributes = data[1:]
-				}
-			}
+			results[k].FlushEvent = decodeFlushEvent(s.orm, event)
 		} else {
 			results[k].Error = event
 			k++
@@ -108,7 +130,23 @@ func (s *asyncFlushEvents) Errors(total int, last bool) []FlushEventWithError {
 
 func (s *asyncFlushEvents) TrimEvents(total int) {
 	r := s.orm.Engine().Redis(s.redisPoolName)
-	r.Ltrim(s.orm, s.listName, int64(total), int64(-total))
+	trimAsyncList(s.orm, r, s.listName, total)
+	for _, stream := range s.streams() {
+		messages := r.XRange(s.orm, stream, "-", "+", int64(total))
+		if len(messages) == 0 {
+			continue
+		}
+		ids := make([]string, len(messages))
+		for i, message := range messages {
+			ids[i] = message.ID
+		}
+		r.XAck(s.orm, stream, asyncStreamsGroup, ids...)
+		r.XDel(s.orm, stream, ids...)
+		total -= len(ids)
+		if total <= 0 {
+			return
+		}
+	}
 }
 
 func (s *asyncFlushEvents) TrimErrors(total int) {
@@ -117,6 +155,188 @@ func (s *asyncFlushEvents) TrimErrors(total int) {
 	r.Ltrim(s.orm, s.listName+flushAsyncEventsListErrorSuffix, int64(total), int64(-total))
 }
 
+func (s *asyncFlushEvents) Retry(total int) (int, error) {
+	return s.moveErrors(func(r RedisCache, errorsList string) []asyncFlushErrorMove {
+		values := r.LRange(s.orm, errorsList, 0, int64(total*2-1))
+		moves := make([]asyncFlushErrorMove, 0, len(values)/2)
+		for i := 0; i+1 < len(values); i += 2 {
+			moves = append(moves, asyncFlushErrorMove{index: i / 2, event: values[i], newEvent: values[i]})
+		}
+		return moves
+	})
+}
+
+func (s *asyncFlushEvents) RetryOne(index int) (bool, error) {
+	moved, err := s.moveErrors(func(r RedisCache, errorsList string) []asyncFlushErrorMove {
+		event, has := s.errorEvent(r, errorsList, index)
+		if !has {
+			return nil
+		}
+		return []asyncFlushErrorMove{{index: index, event: event, newEvent: event}}
+	})
+	return moved == 1, err
+}
+
+func (s *asyncFlushEvents) Discard(filter func(event FlushEventWithError) bool) (int, error) {
+	return s.moveErrors(func(r RedisCache, errorsList string) []asyncFlushErrorMove {
+		var moves []asyncFlushErrorMove
+		for start := 0; ; start += asyncFlushErrorsPage * 2 {
+			values := r.LRange(s.orm, errorsList, int64(start), int64(start+asyncFlushErrorsPage*2-1))
+			for i := 0; i+1 < len(values); i += 2 {
+				event := FlushEventWithError{FlushEvent: decodeFlushEvent(s.orm, values[i]), Error: values[i+1]}
+				if filter(event) {
+					moves = append(moves, asyncFlushErrorMove{index: (start + i) / 2, event: values[i]})
+				}
+			}
+			if len(values) < asyncFlushErrorsPage*2 {
+				return moves
+			}
+		}
+	})
+}
+
+func (s *asyncFlushEvents) EditAndRetry(index int, newSQL string, params ...any) (bool, error) {
+	moved, err := s.moveErrors(func(r RedisCache, errorsList string) []asyncFlushErrorMove {
+		event, has := s.errorEvent(r, errorsList, index)
+		if !has {
+			return nil
+		}
+		return []asyncFlushErrorMove{{index: index, event: event, newEvent: editAsyncEvent(event, newSQL, params)}}
+	})
+	return moved == 1, err
+}
+
+func (s *asyncFlushEvents) errorEvent(r RedisCache, errorsList string, index int) (string, bool) {
+	if index < 0 {
+		return "", false
+	}
+	values := r.LRange(s.orm, errorsList, int64(index*2), int64(index*2+1))
+	if len(values) < 2 {
+		return "", false
+	}
+	return values[0], true
+}
+
+func editAsyncEvent(value, newSQL string, params []any) string {
+	if isStructuredAsyncEvent(value) {
+		event := &asyncEvent{}
+		_ = jsoniter.ConfigFastest.UnmarshalFromString(value, event)
+		event.SQL = newSQL
+		event.Params = params
+		return asyncTemporaryQueueEvent{event}.marshal()
+	}
+	edited := append(asyncTemporaryQueueEvent{newSQL}, params...)
+	flushed, _ := splitAsyncEvent(value)
+	if flushed != "" {
+		var flushedEvents asyncFlushedEvents
+		_ = jsoniter.ConfigFastest.UnmarshalFromString(flushed, &flushedEvents)
+		edited = append(asyncTemporaryQueueEvent{flushedEvents}, edited...)
+	}
+	return edited.marshal()
+}
+
+type asyncFlushErrorMove struct {
+	index    int
+	event    string
+	newEvent string
+}
+
+const asyncFlushErrorsMoveScript = `
+for i = 1, #ARGV, 4 do
+	if redis.call('LINDEX', KEYS[1], tonumber(ARGV[i]) * 2) ~= ARGV[i + 1] then
+		return -1
+	end
+end
+local time = redis.call('TIME')
+local now = time[1] * 1000 + math.floor(time[2] / 1000)
+local moved = 0
+for i = 1, #ARGV, 4 do
+	local index = tonumber(ARGV[i]) * 2
+	local destination = tonumber(ARGV[i + 2])
+	if destination > 0 then
+		if string.find(KEYS[destination], ':stream:', 1, true) then
+			redis.call('XADD', KEYS[destination], '*', 'e', ARGV[i + 3])
+		else
+			redis.call('RPUSH', KEYS[destination], ARGV[i + 3])
+			redis.call('RPUSH', KEYS[destination + 1], '1:' .. now)
+		end
+	end
+	redis.call('LSET', KEYS[1], index, '__beeorm_removed')
+	redis.call('LSET', KEYS[1], index + 1, '__beeorm_removed')
+	moved = moved + 1
+end
+redis.call('LREM', KEYS[1], moved * 2, '__beeorm_removed')
+return moved
+`
+
+func (s *asyncFlushEvents) moveErrors(selector func(r RedisCache, errorsList string) []asyncFlushErrorMove) (int, error) {
+	r := s.orm.Engine().Redis(s.redisPoolName)
+	errorsList := s.listName + flushAsyncEventsListErrorSuffix
+	for attempt := 0; attempt < 10; attempt++ {
+		moves := selector(r, errorsList)
+		if len(moves) == 0 {
+			return 0, nil
+		}
+		keys := []string{errorsList}
+		keysIndex := make(map[string]int)
+		args := make([]any, 0, len(moves)*4)
+		for _, move := range moves {
+			destination := 0
+			if move.newEvent != "" {
+				key := s.retryDestination(move.newEvent)
+				destination = keysIndex[key]
+				if destination == 0 {
+					keys = append(keys, key, key+asyncTimestampsSuffix)
+					destination = len(keys) - 1
+					keysIndex[key] = destination
+				}
+			}
+			args = append(args, move.index, move.event, destination, move.newEvent)
+		}
+		moved, _ := r.Eval(s.orm, asyncFlushErrorsMoveScript, keys, args...).(int64)
+		if moved >= 0 {
+			return int(moved), nil
+		}
+	}
+	return 0, ErrAsyncFlushErrorsChanged
+}
+
+func (s *asyncFlushEvents) retryDestination(event string) string {
+	options := s.orm.Engine().(*engineImplementation).asyncFlushStreams
+	if options == nil {
+		return s.listName
+	}
+	return asyncStreamName(s.listName, s.orm.Engine().(*engineImplementation).asyncStreamPartition(asyncJSONEventEntityID(event)))
+}
+
+func decodeFlushEvent(orm ORM, value string) FlushEvent {
+	if isStructuredAsyncEvent(value) {
+		event, schema, err := decodeAsyncEvent(orm, value)
+		if err != nil {
+			return FlushEvent{}
+		}
+		sql, params := event.buildSQL(schema)
+		attributes := make([]string, len(params))
+		for i, param := range params {
+			if param != nil {
+				attributes[i] = fmt.Sprintf("%v", param)
+			}
+		}
+		return FlushEvent{SQL: sql, QueryAttributes: attributes}
+	}
+	_, value = splitAsyncEvent(value)
+	var data []string
+	_ = jsoniter.ConfigFastest.UnmarshalFromString(value, &data)
+	event := FlushEvent{}
+	if len(data) > 0 {
+		event.SQL = data[0]
+		if len(data) > 1 {
+			event.QueryAttributes = data[1:]
+		}
+	}
+	return event
+}
+
 func ReadAsyncFlushEvents(orm ORM) []AsyncFlushEvents {
 	stats := make([]AsyncFlushEvents, 0)
 	mapped := make(map[string]*asyncFlushEvents)
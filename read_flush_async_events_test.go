@@ -3,6 +3,7 @@ package beeorm
 import (
 	"strconv"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -130,3 +131,128 @@ func TestAsyncGrouped(t *testing.T) {
 
 	}
 }
+
+func TestAsyncRetryErrors(t *testing.T) {
+	registry := NewRegistry()
+	orm := PrepareTables(t, registry, flushEntityAsyncStats{})
+	schema := getEntitySchema[flushEntityAsyncStats](orm)
+	schema.DisableCache(true, true)
+
+	for i := 0; i < 5; i++ {
+		entity := NewEntity[flushEntityAsyncStats](orm)
+		entity.Name = "test " + strconv.Itoa(i)
+		assert.NoError(t, orm.FlushAsync())
+	}
+	schema.GetDB().Exec(orm, "ALTER TABLE flushEntityAsyncStats DROP COLUMN Name")
+	assert.NoError(t, runAsyncConsumer(orm, false))
+	stat := ReadAsyncFlushEvents(orm)[0]
+	assert.Equal(t, uint64(5), stat.ErrorsCount())
+
+	retried, err := stat.RetryOne(10)
+	assert.NoError(t, err)
+	assert.False(t, retried)
+	retried, err = stat.EditAndRetry(-1, "")
+	assert.NoError(t, err)
+	assert.False(t, retried)
+	discarded, err := stat.Discard(func(event FlushEventWithError) bool {
+		return event.QueryAttributes[1] == "test 4"
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, discarded)
+	assert.Equal(t, uint64(4), stat.ErrorsCount())
+
+	schema.GetDB().Exec(orm, "ALTER TABLE flushEntityAsyncStats ADD COLUMN Name varchar(255) NOT NULL DEFAULT ''")
+	retried, err = stat.RetryOne(1)
+	assert.NoError(t, err)
+	assert.True(t, retried)
+	assert.Equal(t, uint64(3), stat.ErrorsCount())
+	assert.Equal(t, uint64(1), stat.EventsCount())
+	errors := stat.Errors(10, false)
+	assert.Contains(t, errors[0].QueryAttributes[1], "test 0")
+	assert.Contains(t, errors[1].QueryAttributes[1], "test 2")
+
+	retried, err = stat.EditAndRetry(0, "INSERT INTO `flushEntityAsyncStats`(`ID`,`Name`) VALUES(?,?)", 1, "edited")
+	assert.NoError(t, err)
+	assert.True(t, retried)
+	moved, err := stat.Retry(1)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, moved)
+	assert.Equal(t, uint64(1), stat.ErrorsCount())
+	assert.Equal(t, uint64(3), stat.EventsCount())
+	assert.NoError(t, ConsumeAsyncFlushEvents(orm, false))
+	assert.Equal(t, uint64(0), stat.EventsCount())
+	moved, err = stat.Retry(10)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, moved)
+	assert.NoError(t, ConsumeAsyncFlushEvents(orm, false))
+	assert.Equal(t, uint64(0), stat.ErrorsCount())
+
+	events := stat.(*asyncFlushEvents)
+	r := orm.Engine().Redis(events.redisPoolName)
+	r.RPush(orm, events.listName+flushAsyncEventsListErrorSuffix, "[\"INSERT INTO `flushEntityAsyncStats`(`ID`,`Name`) VALUES(?,?)\",10,\"legacy\"]", "error")
+	assert.Equal(t, uint64(1), stat.ErrorsCount())
+	retried, err = stat.EditAndRetry(0, "INSERT INTO `flushEntityAsyncStats`(`ID`,`Name`) VALUES(?,?)", 10, "legacy edited")
+	assert.NoError(t, err)
+	assert.True(t, retried)
+	assert.Equal(t, uint64(0), stat.ErrorsCount())
+	assert.NoError(t, ConsumeAsyncFlushEvents(orm, false))
+
+	rows := Search[flushEntityAsyncStats](orm, NewWhere("1 ORDER BY ID"), nil)
+	assert.Equal(t, 5, rows.Len())
+	names := make([]string, 0, 5)
+	for rows.Next() {
+		names = append(names, rows.Entity().Name)
+	}
+	assert.Equal(t, []string{"edited", "test 1", "test 2", "test 3", "legacy edited"}, names)
+}
+
+func TestAsyncStats(t *testing.T) {
+	registry := NewRegistry()
+	orm := PrepareTables(t, registry, flushEntityAsyncStats{})
+	schema := getEntitySchema[flushEntityAsyncStats](orm)
+	schema.DisableCache(true, true)
+	stat := ReadAsyncFlushEvents(orm)[0]
+
+	stats := stat.Stats()
+	assert.Equal(t, uint64(0), stats.Pending)
+	assert.Equal(t, time.Duration(0), stats.OldestEventAge)
+	assert.Equal(t, uint64(0), stats.EventsApplied)
+
+	for i := 0; i < 10; i++ {
+		entity := NewEntity[flushEntityAsyncStats](orm)
+		entity.Name = "test " + strconv.Itoa(i)
+		assert.NoError(t, orm.FlushAsync())
+	}
+	stop := ConsumeAsyncBuffer(orm, func(error) {})
+	stop()
+	time.Sleep(time.Millisecond * 20)
+	stats = stat.Stats()
+	assert.Equal(t, uint64(10), stats.Pending)
+	assert.Greater(t, stats.OldestEventAge, time.Millisecond*10)
+	assert.Less(t, stats.OldestEventAge, time.Second*5)
+
+	assert.NoError(t, ConsumeAsyncFlushEvents(orm, false))
+	stats = stat.Stats()
+	assert.Equal(t, uint64(0), stats.Pending)
+	assert.Equal(t, time.Duration(0), stats.OldestEventAge)
+	assert.Equal(t, uint64(10), stats.EventsApplied)
+	assert.Equal(t, uint64(1), stats.BatchesCommitted)
+	assert.Equal(t, uint64(0), stats.OneByOneFallbacks)
+	assert.Len(t, stats.SkippedErrorCodes, 0)
+
+	for i := 0; i < 3; i++ {
+		entity := NewEntity[flushEntityAsyncStats](orm)
+		entity.Name = "test " + strconv.Itoa(i)
+		assert.NoError(t, orm.FlushAsync())
+	}
+	schema.GetDB().Exec(orm, "ALTER TABLE flushEntityAsyncStats DROP COLUMN Name")
+	assert.NoError(t, runAsyncConsumer(orm, false))
+	stats = stat.Stats()
+	assert.Equal(t, uint64(10), stats.EventsApplied)
+	assert.Equal(t, uint64(1), stats.BatchesCommitted)
+	assert.Equal(t, uint64(1), stats.OneByOneFallbacks)
+	assert.Equal(t, map[uint16]uint64{1054: 3}, stats.SkippedErrorCodes)
+
+	time.Sleep(time.Second)
+	assert.Greater(t, stat.Stats().EventsPerSecond, float64(0))
+}
@@ -16,6 +16,7 @@ type RedisCache interface {
 	Set(orm ORM, key string, value any, expiration time.Duration)
 	MSet(orm ORM, pairs ...any)
 	Del(orm ORM, keys ...string)
+	Publish(orm ORM, channel string, message any) int64
 	HSet(orm ORM, key string, values ...any)
 	HDel(orm ORM, key string, keys ...string)
 	GetSet(orm ORM, key string, expiration time.Duration, provider func() any) any
@@ -32,6 +33,7 @@ type RedisCache interface {
 	RPush(orm ORM, key string, values ...any) int64
 	LLen(orm ORM, key string) int64
 	Exists(orm ORM, keys ...string) int64
+	Scan(orm ORM, cursor uint64, match string, count int64) (keys []string, next uint64)
 	Type(orm ORM, key string) string
 	LRange(orm ORM, key string, start, stop int64) []string
 	LSet(orm ORM, key string, index int64, value any)
@@ -43,6 +45,7 @@ type RedisCache interface {
 	HSetNx(orm ORM, key, field string, value any) bool
 	HMGet(orm ORM, key string, fields ...string) map[string]any
 	HGetAll(orm ORM, key string) map[string]string
+	HScan(orm ORM, key string, cursor uint64, match string, count int64) (fields map[string]string, next uint64)
 	HGet(orm ORM, key, field string) (value string, has bool)
 	HLen(orm ORM, key string) int64
 	HIncrBy(orm ORM, key, field string, incr int64) int64
@@ -81,6 +84,7 @@ type RedisCache interface {
 	XLen(orm ORM, stream string) int64
 	XClaim(orm ORM, a *redis.XClaimArgs) []redis.XMessage
 	XClaimJustID(orm ORM, a *redis.XClaimArgs) []string
+	XAutoClaim(orm ORM, a *redis.XAutoClaimArgs) (messages []redis.XMessage, start string)
 	XAck(orm ORM, stream, group string, ids ...string) int64
 	FlushAll(orm ORM)
 	FlushDB(orm ORM)
@@ -152,7 +156,7 @@ func (r *redisCache) Eval(orm ORM, script string, keys []string, args ...any) an
 	start := getNow(hasLogger)
 	res, err := r.client.Eval(orm.Context(), script, keys, args...).Result()
 	if hasLogger {
-		message := fmt.Sprintf("EVAL "+script+" %v %v", keys, args)
+		message := fmt.Sprintf("EVAL %s %v %v", script, keys, args)
 		r.fillLogFields(orm, "EVAL", message, start, false, err)
 	}
 	checkError(err)
@@ -164,7 +168,7 @@ func (r *redisCache) EvalSha(orm ORM, sha1 string, keys []string, args ...any) (
 	start := getNow(hasLogger)
 	res, err := r.client.EvalSha(orm.Context(), sha1, keys, args...).Result()
 	if hasLogger {
-		message := fmt.Sprintf("EVALSHA "+sha1+" %v %v", keys, args)
+		message := fmt.Sprintf("EVALSHA %s %v %v", sha1, keys, args)
 		r.fillLogFields(orm, "EVALSHA", message, start, false, err)
 	}
 	if err != nil && !r.ScriptExists(orm, sha1) {
@@ -282,6 +286,18 @@ func (r *redisCache) Exists(orm ORM, keys ...string) int64 {
 	return val
 }
 
+func (r *redisCache) Scan(orm ORM, cursor uint64, match string, count int64) (keys []string, next uint64) {
+	hasLogger, _ := orm.getRedisLoggers()
+	start := getNow(hasLogger)
+	keys, next, err := r.client.Scan(orm.Context(), cursor, match, count).Result()
+	if hasLogger {
+		message := fmt.Sprintf("SCAN %d MATCH %s COUNT %d", cursor, match, count)
+		r.fillLogFields(orm, "SCAN", message, start, false, err)
+	}
+	checkError(err)
+	return keys, next
+}
+
 func (r *redisCache) Type(orm ORM, key string) string {
 	hasLogger, _ := orm.getRedisLoggers()
 	start := getNow(hasLogger)
@@ -450,6 +466,22 @@ func (r *redisCache) HGetAll(orm ORM, key string) map[string]string {
 	return val
 }
 
+func (r *redisCache) HScan(orm ORM, key string, cursor uint64, match string, count int64) (fields map[string]string, next uint64) {
+	hasLogger, _ := orm.getRedisLoggers()
+	start := getNow(hasLogger)
+	values, next, err := r.client.HScan(orm.Context(), key, cursor, match, count).Result()
+	if hasLogger {
+		message := fmt.Sprintf("HSCAN %s %d MATCH %s COUNT %d", key, cursor, match, count)
+		r.fillLogFields(orm, "HSCAN", message, start, false, err)
+	}
+	checkError(err)
+	fields = make(map[string]string, len(values)/2)
+	for i := 0; i+1 < len(values); i += 2 {
+		fields[values[i]] = values[i+1]
+	}
+	return fields, next
+}
+
 func (r *redisCache) HGet(orm ORM, key, field string) (value string, has bool) {
 	hasLogger, _ := orm.getRedisLoggers()
 	misses := false
@@ -746,6 +778,17 @@ func (r *redisCache) Del(orm ORM, keys ...string) {
 	checkError(err)
 }
 
+func (r *redisCache) Publish(orm ORM, channel string, message any) int64 {
+	hasLogger, _ := orm.getRedisLoggers()
+	start := getNow(hasLogger)
+	res, err := r.client.Publish(orm.Context(), channel, message).Result()
+	if hasLogger {
+		r.fillLogFields(orm, "PUBLISH", fmt.Sprintf("PUBLISH %s %v", channel, message), start, false, err)
+	}
+	checkError(err)
+	return res
+}
+
 func (r *redisCache) XTrim(orm ORM, stream string, maxLen int64) (deleted int64) {
 	hasLogger, _ := orm.getRedisLoggers()
 	start := getNow(hasLogger)
@@ -1002,6 +1045,19 @@ func (r *redisCache) XClaimJustID(orm ORM, a *redis.XClaimArgs) []string {
 	return res
 }
 
+func (r *redisCache) XAutoClaim(orm ORM, a *redis.XAutoClaimArgs) (messages []redis.XMessage, start string) {
+	hasLogger, _ := orm.getRedisLoggers()
+	s := getNow(hasLogger)
+	messages, start, err := r.client.XAutoClaim(orm.Context(), a).Result()
+	if hasLogger {
+		message := fmt.Sprintf("XAUTOCLAIM %s %s %s", a.Stream, a.Group, a.Consumer)
+		message += fmt.Sprintf(" MINIDLE %s START %s COUNT %d", a.MinIdle.String(), a.Start, a.Count)
+		r.fillLogFields(orm, "XAUTOCLAIM", message, s, false, err)
+	}
+	checkError(err)
+	return messages, start
+}
+
 func (r *redisCache) XAck(orm ORM, stream, group string, ids ...string) int64 {
 	hasLogger, _ := orm.getRedisLoggers()
 	start := getNow(hasLogger)
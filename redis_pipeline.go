@@ -19,7 +19,7 @@ type RedisPipeLine struct {
 
 func (rp *RedisPipeLine) LPush(key string, values ...any) {
 	rp.commands++
-	hasLog, _ := rp.orm.getRedisLoggers()
+	hasLog := rp.hasLog()
 	if hasLog {
 		rp.log = append(rp.log, fmt.Sprintf("LPUSH %s %v", key, values))
 	}
@@ -28,7 +28,7 @@ func (rp *RedisPipeLine) LPush(key string, values ...any) {
 
 func (rp *RedisPipeLine) RPush(key string, values ...any) {
 	rp.commands++
-	hasLog, _ := rp.orm.getRedisLoggers()
+	hasLog := rp.hasLog()
 	if hasLog {
 		rp.log = append(rp.log, fmt.Sprintf("RPUSH %s %v", key, values))
 	}
@@ -37,16 +37,25 @@ func (rp *RedisPipeLine) RPush(key string, values ...any) {
 
 func (rp *RedisPipeLine) LSet(key string, index int64, value any) {
 	rp.commands++
-	hasLog, _ := rp.orm.getRedisLoggers()
+	hasLog := rp.hasLog()
 	if hasLog {
 		rp.log = append(rp.log, fmt.Sprintf("LSET %s %d %v", key, index, value))
 	}
 	rp.pipeLine.LSet(rp.orm.Context(), key, index, value)
 }
 
+func (rp *RedisPipeLine) Eval(script string, keys []string, args ...any) {
+	rp.commands++
+	hasLog := rp.hasLog()
+	if hasLog {
+		rp.log = append(rp.log, fmt.Sprintf("EVAL %s %v %v", script, keys, args))
+	}
+	rp.pipeLine.Eval(rp.orm.Context(), script, keys, args...)
+}
+
 func (rp *RedisPipeLine) Del(key ...string) {
 	rp.commands++
-	hasLog, _ := rp.orm.getRedisLoggers()
+	hasLog := rp.hasLog()
 	if hasLog {
 		rp.log = append(rp.log, "DEL "+strings.Join(key, " "))
 	}
@@ -55,7 +64,7 @@ func (rp *RedisPipeLine) Del(key ...string) {
 
 func (rp *RedisPipeLine) Get(key string) *PipeLineGet {
 	rp.commands++
-	hasLog, _ := rp.orm.getRedisLoggers()
+	hasLog := rp.hasLog()
 	if hasLog {
 		rp.log = append(rp.log, "GET "+key)
 	}
@@ -64,16 +73,34 @@ func (rp *RedisPipeLine) Get(key string) *PipeLineGet {
 
 func (rp *RedisPipeLine) LRange(key string, start, stop int64) *PipeLineSlice {
 	rp.commands++
-	hasLog, _ := rp.orm.getRedisLoggers()
+	hasLog := rp.hasLog()
 	if hasLog {
 		rp.log = append(rp.log, fmt.Sprintf("LRANGE %s %d %d", key, start, stop))
 	}
 	return &PipeLineSlice{p: rp, cmd: rp.pipeLine.LRange(rp.orm.Context(), key, start, stop)}
 }
 
+func (rp *RedisPipeLine) SMembers(key string) *PipeLineSlice {
+	rp.commands++
+	hasLog := rp.hasLog()
+	if hasLog {
+		rp.log = append(rp.log, "SMEMBERS "+key)
+	}
+	return &PipeLineSlice{p: rp, cmd: rp.pipeLine.SMembers(rp.orm.Context(), key)}
+}
+
+func (rp *RedisPipeLine) HGet(key, field string) *PipeLineGet {
+	rp.commands++
+	hasLog := rp.hasLog()
+	if hasLog {
+		rp.log = append(rp.log, "HGET "+key+" "+field)
+	}
+	return &PipeLineGet{p: rp, cmd: rp.pipeLine.HGet(rp.orm.Context(), key, field)}
+}
+
 func (rp *RedisPipeLine) Set(key string, value any, expiration time.Duration) {
 	rp.commands++
-	hasLog, _ := rp.orm.getRedisLoggers()
+	hasLog := rp.hasLog()
 	if hasLog {
 		rp.log = append(rp.log, fmt.Sprintf("SET %s %v %s", key, value, expiration.String()))
 	}
@@ -82,7 +109,7 @@ func (rp *RedisPipeLine) Set(key string, value any, expiration time.Duration) {
 
 func (rp *RedisPipeLine) SAdd(key string, members ...any) {
 	rp.commands++
-	hasLog, _ := rp.orm.getRedisLoggers()
+	hasLog := rp.hasLog()
 	if hasLog {
 		rp.log = append(rp.log, fmt.Sprintf("SADD %s %v", key, members))
 	}
@@ -91,16 +118,25 @@ func (rp *RedisPipeLine) SAdd(key string, members ...any) {
 
 func (rp *RedisPipeLine) SRem(key string, members ...any) {
 	rp.commands++
-	hasLog, _ := rp.orm.getRedisLoggers()
+	hasLog := rp.hasLog()
 	if hasLog {
 		rp.log = append(rp.log, fmt.Sprintf("SREM %s %v", key, members))
 	}
 	rp.pipeLine.SRem(rp.orm.Context(), key, members...)
 }
 
+func (rp *RedisPipeLine) RenameNX(key, newKey string) {
+	rp.commands++
+	hasLog := rp.hasLog()
+	if hasLog {
+		rp.log = append(rp.log, fmt.Sprintf("RENAMENX %s %s", key, newKey))
+	}
+	rp.pipeLine.RenameNX(rp.orm.Context(), key, newKey)
+}
+
 func (rp *RedisPipeLine) MSet(pairs ...any) {
 	rp.commands++
-	hasLog, _ := rp.orm.getRedisLoggers()
+	hasLog := rp.hasLog()
 	if hasLog {
 		message := "MSET"
 		for _, v := range pairs {
@@ -113,7 +149,7 @@ func (rp *RedisPipeLine) MSet(pairs ...any) {
 
 func (rp *RedisPipeLine) Expire(key string, expiration time.Duration) *PipeLineBool {
 	rp.commands++
-	hasLog, _ := rp.orm.getRedisLoggers()
+	hasLog := rp.hasLog()
 	if hasLog {
 		rp.log = append(rp.log, fmt.Sprintf("EXPIRE %s %s", key, expiration.String()))
 	}
@@ -122,16 +158,25 @@ func (rp *RedisPipeLine) Expire(key string, expiration time.Duration) *PipeLineB
 
 func (rp *RedisPipeLine) HIncrBy(key, field string, incr int64) *PipeLineInt {
 	rp.commands++
-	hasLog, _ := rp.orm.getRedisLoggers()
+	hasLog := rp.hasLog()
 	if hasLog {
 		rp.log = append(rp.log, fmt.Sprintf("HINCRBY %s %s %d", key, field, incr))
 	}
 	return &PipeLineInt{p: rp, cmd: rp.pipeLine.HIncrBy(rp.orm.Context(), key, field, incr)}
 }
 
+func (rp *RedisPipeLine) IncrBy(key string, incr int64) *PipeLineInt {
+	rp.commands++
+	hasLog := rp.hasLog()
+	if hasLog {
+		rp.log = append(rp.log, fmt.Sprintf("INCRBY %s %d", key, incr))
+	}
+	return &PipeLineInt{p: rp, cmd: rp.pipeLine.IncrBy(rp.orm.Context(), key, incr)}
+}
+
 func (rp *RedisPipeLine) HSet(key string, values ...any) {
 	rp.commands++
-	hasLog, _ := rp.orm.getRedisLoggers()
+	hasLog := rp.hasLog()
 	if hasLog {
 		rp.log = append(rp.log, fmt.Sprintf("HSET %s %v", key, values))
 	}
@@ -140,7 +185,7 @@ func (rp *RedisPipeLine) HSet(key string, values ...any) {
 
 func (rp *RedisPipeLine) HDel(key string, values ...string) {
 	rp.commands++
-	hasLog, _ := rp.orm.getRedisLoggers()
+	hasLog := rp.hasLog()
 	if hasLog {
 		rp.log = append(rp.log, fmt.Sprintf("HDEL %s %s", key, strings.Join(values, " ")))
 	}
@@ -149,7 +194,7 @@ func (rp *RedisPipeLine) HDel(key string, values ...string) {
 
 func (rp *RedisPipeLine) XAdd(stream string, values []string) *PipeLineString {
 	rp.commands++
-	hasLog, _ := rp.orm.getRedisLoggers()
+	hasLog := rp.hasLog()
 	if hasLog {
 		rp.log = append(rp.log, fmt.Sprintf("XADD %s %s", stream, strings.Join(values, " ")))
 	}
@@ -160,6 +205,16 @@ func (rp *RedisPipeLine) Exec(orm ORM) {
 	if rp.commands == 0 {
 		return
 	}
+	plan := rp.orm.(*ormImplementation).plan
+	if plan != nil {
+		for _, command := range rp.log {
+			plan.RedisCommands = append(plan.RedisCommands, FlushPlanRedisCommand{Pool: rp.pool, Command: command})
+		}
+		rp.pipeLine.Discard()
+		rp.log = nil
+		rp.commands = 0
+		return
+	}
 	hasLog, loggers := rp.orm.getRedisLoggers()
 	start := getNow(hasLog)
 	_, err := rp.pipeLine.Exec(rp.orm.Context())
@@ -176,6 +231,14 @@ func (rp *RedisPipeLine) Exec(orm ORM) {
 	checkError(err)
 }
 
+func (rp *RedisPipeLine) hasLog() bool {
+	if rp.orm.(*ormImplementation).plan != nil {
+		return true
+	}
+	hasLog, _ := rp.orm.getRedisLoggers()
+	return hasLog
+}
+
 type PipeLineGet struct {
 	p   *RedisPipeLine
 	cmd *redis.StringCmd
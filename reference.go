@@ -14,8 +14,9 @@ type referenceInterface interface {
 }
 
 type referenceDefinition struct {
-	Cached bool
-	Type   reflect.Type
+	Cached   bool
+	Type     reflect.Type
+	OnDelete string
 }
 
 type Reference[E any] uint64
@@ -0,0 +1,181 @@
+package beeorm
+
+import (
+	"fmt"
+	"hash/maphash"
+	"maps"
+	"reflect"
+
+	"github.com/puzpuzpuz/xsync/v2"
+)
+
+const (
+	onDeleteCascade  = "cascade"
+	onDeleteSetNull  = "setNull"
+	onDeleteRestrict = "restrict"
+)
+
+type referenceAction struct {
+	schema   *entitySchema
+	column   string
+	onDelete string
+}
+
+type ReferenceRestrictError struct {
+	Schema          EntitySchema
+	ID              uint64
+	ReferenceSchema EntitySchema
+	ReferenceID     uint64
+	ReferenceColumn string
+}
+
+func (e *ReferenceRestrictError) Error() string {
+	return fmt.Sprintf("can't delete %s with ID %d, referenced by %s with ID %d in field %s",
+		e.Schema.GetType().String(), e.ID, e.ReferenceSchema.GetType().String(), e.ReferenceID, e.ReferenceColumn)
+}
+
+func (orm *ormImplementation) handleReferenceActions() (rollback func(), err error) {
+	var restore []func()
+	rollback = func() {
+		for i := len(restore) - 1; i >= 0; i-- {
+			restore[i]()
+		}
+	}
+	actions := orm.engine.registry.referenceActions
+	if len(actions) == 0 || orm.trackedEntities == nil {
+		return rollback, nil
+	}
+	processed := make(map[*entitySchema]map[uint64]bool)
+	for {
+		deletes := make(map[*entitySchema][]*removableEntity)
+		orm.trackedEntities.Range(func(_ uint64, entities *xsync.MapOf[uint64, EntityFlush]) bool {
+			entities.Range(func(id uint64, flush EntityFlush) bool {
+				toRemove, isDelete := flush.(*removableEntity)
+				if !isDelete || len(actions[toRemove.schema.t]) == 0 || processed[toRemove.schema][id] {
+					return true
+				}
+				if processed[toRemove.schema] == nil {
+					processed[toRemove.schema] = make(map[uint64]bool)
+				}
+				processed[toRemove.schema][id] = true
+				deletes[toRemove.schema] = append(deletes[toRemove.schema], toRemove)
+				return true
+			})
+			return true
+		})
+		if len(deletes) == 0 {
+			return rollback, nil
+		}
+		for schema, removed := range deletes {
+			for _, action := range actions[schema.t] {
+				err = orm.applyReferenceAction(schema, action, removed, &restore)
+				if err != nil {
+					rollback()
+					return nil, err
+				}
+			}
+		}
+	}
+}
+
+func (orm *ormImplementation) applyReferenceAction(schema *entitySchema, action referenceAction, removed []*removableEntity, restore *[]func()) error {
+	ids := make([]uint64, len(removed))
+	removedByID := make(map[uint64]*removableEntity, len(removed))
+	for i, toRemove := range removed {
+		ids[i] = toRemove.id
+		removedByID[toRemove.id] = toRemove
+	}
+	where := NewWhere("`"+action.column+"` IN ?", ids)
+	iterator, _ := action.schema.search(orm, where, nil, false)
+	for iterator.Next() {
+		child := iterator.Entity()
+		if child == nil {
+			continue
+		}
+		childValue := reflect.ValueOf(child).Elem()
+		childID := childValue.Field(0).Uint()
+		if orm.isTrackedForDelete(action.schema, childID) {
+			continue
+		}
+		parentID := action.schema.fieldGetters[action.column](childValue).(IDGetter).GetID()
+		switch action.onDelete {
+		case onDeleteRestrict:
+			return &ReferenceRestrictError{Schema: schema, ID: parentID, ReferenceSchema: action.schema, ReferenceID: childID, ReferenceColumn: action.column}
+		case onDeleteCascade:
+			*restore = append(*restore, orm.trackedEntitySnapshot(action.schema, childID, ""))
+			toRemove := &removableEntity{force: removedByID[parentID].force}
+			toRemove.orm = orm
+			toRemove.schema = action.schema
+			toRemove.source = child
+			toRemove.value = childValue
+			toRemove.id = childID
+			orm.storeTrackedEntity(toRemove)
+		case onDeleteSetNull:
+			*restore = append(*restore, orm.trackedEntitySnapshot(action.schema, childID, action.column))
+			err := orm.trackEntityField(child, action.column, nil)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (orm *ormImplementation) trackedEntitySnapshot(schema *entitySchema, id uint64, column string) func() {
+	entities, _ := orm.trackedEntities.LoadOrCompute(schema.index, func() *xsync.MapOf[uint64, EntityFlush] {
+		return xsync.NewTypedMapOf[uint64, EntityFlush](func(seed maphash.Seed, u uint64) uint64 {
+			return u
+		})
+	})
+	tracked, has := entities.Load(id)
+	if !has {
+		return func() {
+			entities.Delete(id)
+		}
+	}
+	switch flush := tracked.(type) {
+	case *editableFields:
+		newBind := maps.Clone(flush.newBind)
+		oldBind := maps.Clone(flush.oldBind)
+		increments := maps.Clone(flush.increments)
+		return func() {
+			flush.newBind = newBind
+			flush.oldBind = oldBind
+			flush.increments = increments
+			entities.Store(id, flush)
+		}
+	case *editableEntity:
+		return trackedEntityFieldSnapshot(entities, tracked, flush.value.Elem(), schema, column)
+	case *insertableEntity:
+		return trackedEntityFieldSnapshot(entities, tracked, flush.value.Elem(), schema, column)
+	}
+	return func() {
+		entities.Store(id, tracked)
+	}
+}
+
+func trackedEntityFieldSnapshot(entities *xsync.MapOf[uint64, EntityFlush], tracked EntityFlush, elem reflect.Value, schema *entitySchema, column string) func() {
+	if column == "" {
+		return func() {
+			entities.Store(tracked.ID(), tracked)
+		}
+	}
+	value, _ := schema.fieldBindSetters[column](schema.fieldGetters[column](elem))
+	return func() {
+		schema.fieldSetters[column](value, elem)
+		entities.Store(tracked.ID(), tracked)
+	}
+}
+
+func (orm *ormImplementation) isTrackedForDelete(schema *entitySchema, id uint64) bool {
+	entities, has := orm.trackedEntities.Load(schema.index)
+	if !has {
+		return false
+	}
+	tracked, has := entities.Load(id)
+	if !has {
+		return false
+	}
+	_, isDelete := tracked.(*removableEntity)
+	return isDelete
+}
@@ -0,0 +1,124 @@
+package beeorm
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type referenceActionsParent struct {
+	ID   uint64 `orm:"localCache;redisCache"`
+	Name string
+}
+
+type referenceActionsCascade struct {
+	ID     uint64                            `orm:"localCache;redisCache"`
+	Parent Reference[referenceActionsParent] `orm:"required;onDelete=cascade;cached"`
+}
+
+type referenceActionsCascadeChild struct {
+	ID     uint64                             `orm:"localCache;redisCache"`
+	Parent Reference[referenceActionsCascade] `orm:"required;onDelete=cascade"`
+}
+
+type referenceActionsSetNull struct {
+	ID     uint64                            `orm:"localCache;redisCache"`
+	Parent Reference[referenceActionsParent] `orm:"onDelete=setNull;cached"`
+}
+
+type referenceActionsRestrict struct {
+	ID     uint64                            `orm:"localCache;redisCache"`
+	Parent Reference[referenceActionsParent] `orm:"onDelete=restrict"`
+}
+
+func TestReferenceActionsNoCache(t *testing.T) {
+	testReferenceActions(t, false, false)
+}
+
+func TestReferenceActionsLocalCache(t *testing.T) {
+	testReferenceActions(t, true, false)
+}
+
+func TestReferenceActionsRedis(t *testing.T) {
+	testReferenceActions(t, false, true)
+}
+
+func TestReferenceActionsLocalCacheRedis(t *testing.T) {
+	testReferenceActions(t, true, true)
+}
+
+func testReferenceActions(t *testing.T, local, redis bool) {
+	var parent *referenceActionsParent
+	var cascade *referenceActionsCascade
+	var cascadeChild *referenceActionsCascadeChild
+	var setNull *referenceActionsSetNull
+	var restrict *referenceActionsRestrict
+	orm := PrepareTables(t, NewRegistry(), parent, cascade, cascadeChild, setNull, restrict)
+	for _, schema := range orm.Engine().Registry().Entities() {
+		schema.DisableCache(!local, !redis)
+	}
+
+	parent = NewEntity[referenceActionsParent](orm)
+	parent.Name = "parent"
+	cascade = NewEntity[referenceActionsCascade](orm)
+	cascade.Parent = Reference[referenceActionsParent](parent.ID)
+	cascadeChild = NewEntity[referenceActionsCascadeChild](orm)
+	cascadeChild.Parent = Reference[referenceActionsCascade](cascade.ID)
+	setNull = NewEntity[referenceActionsSetNull](orm)
+	setNull.Parent = Reference[referenceActionsParent](parent.ID)
+	restrict = NewEntity[referenceActionsRestrict](orm)
+	restrict.Parent = Reference[referenceActionsParent](parent.ID)
+	assert.NoError(t, orm.Flush())
+	assert.Equal(t, 1, GetByReference[referenceActionsCascade](orm, "Parent", parent.ID).Len())
+	assert.Equal(t, 1, GetByReference[referenceActionsSetNull](orm, "Parent", parent.ID).Len())
+
+	DeleteEntity(orm, parent)
+	err := orm.Flush()
+	assert.EqualError(t, err, fmt.Sprintf("can't delete beeorm.referenceActionsParent with ID %d, referenced by beeorm.referenceActionsRestrict with ID %d in field Parent", parent.ID, restrict.ID))
+	restrictError, isRestrictError := err.(*ReferenceRestrictError)
+	assert.True(t, isRestrictError)
+	assert.Equal(t, parent.ID, restrictError.ID)
+	assert.Equal(t, restrict.ID, restrictError.ReferenceID)
+	assert.False(t, orm.(*ormImplementation).isTrackedForDelete(getEntitySchema[referenceActionsCascade](orm), cascade.ID))
+	orm.ClearFlush()
+	_, found := GetByID[referenceActionsParent](orm, parent.ID)
+	assert.True(t, found)
+	_, found = GetByID[referenceActionsCascade](orm, cascade.ID)
+	assert.True(t, found)
+
+	DeleteEntity(orm, parent)
+	DeleteEntity(orm, restrict)
+	_, err = orm.FlushPlan()
+	assert.NoError(t, err)
+	assert.False(t, orm.(*ormImplementation).isTrackedForDelete(getEntitySchema[referenceActionsCascade](orm), cascade.ID))
+	assert.NoError(t, orm.Flush())
+	_, found = GetByID[referenceActionsParent](orm, parent.ID)
+	assert.False(t, found)
+	_, found = GetByID[referenceActionsCascade](orm, cascade.ID)
+	assert.False(t, found)
+	_, found = GetByID[referenceActionsCascadeChild](orm, cascadeChild.ID)
+	assert.False(t, found)
+	assert.Equal(t, 0, GetByReference[referenceActionsCascade](orm, "Parent", parent.ID).Len())
+	assert.Equal(t, 0, GetByReference[referenceActionsSetNull](orm, "Parent", parent.ID).Len())
+	setNull, found = GetByID[referenceActionsSetNull](orm, setNull.ID)
+	assert.True(t, found)
+	assert.Equal(t, uint64(0), setNull.Parent.GetID())
+
+	_, err = DeleteWhere[referenceActionsParent](orm, NewWhere("1"), nil)
+	assert.ErrorContains(t, err, "use DeleteEntity instead")
+}
+
+type invalidReferenceActionsEntity struct {
+	ID     uint64
+	Parent Reference[referenceActionsParent] `orm:"required;onDelete=setNull"`
+}
+
+func TestReferenceActionsInvalidTag(t *testing.T) {
+	registry := NewRegistry()
+	registry.RegisterMySQL("root:root@tcp(localhost:3377)/test", DefaultPoolCode, &MySQLOptions{})
+	registry.RegisterEntity(&referenceActionsParent{}, &invalidReferenceActionsEntity{})
+	assert.PanicsWithError(t, "beeorm.invalidReferenceActionsEntity field Parent with onDelete=setNull can't be required", func() {
+		_, _ = registry.Validate()
+	})
+}
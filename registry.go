@@ -26,15 +26,25 @@ type Registry interface {
 	RegisterRedis(address string, db int, poolCode string, options *RedisOptions)
 	InitByYaml(yaml map[string]any) error
 	SetOption(key string, value any)
+	SetFlushRetryPolicy(policy *FlushRetryPolicy)
+	SetAsyncFlushStreams(options *AsyncFlushStreamsOptions)
+	SetAsyncErrorPolicy(policy *AsyncErrorPolicy)
+	SetAsyncBuffer(options *AsyncBufferOptions)
+	SetLocalCacheInvalidation(options *LocalCacheInvalidationOptions)
 }
 
 type registry struct {
-	mysqlPools  map[string]MySQLConfig
-	localCaches map[string]LocalCache
-	redisPools  map[string]RedisPoolConfig
-	entities    map[string]reflect.Type
-	plugins     []any
-	options     map[string]any
+	mysqlPools        map[string]MySQLConfig
+	localCaches       map[string]LocalCache
+	redisPools        map[string]RedisPoolConfig
+	entities          map[string]reflect.Type
+	plugins           []any
+	options           map[string]any
+	flushRetryPolicy  *FlushRetryPolicy
+	asyncFlushStreams *AsyncFlushStreamsOptions
+	asyncErrorPolicy  *AsyncErrorPolicy
+	asyncBuffer       *AsyncBufferOptions
+	localCacheBus     *LocalCacheInvalidationOptions
 }
 
 func NewRegistry() Registry {
@@ -59,6 +69,7 @@ func (r *registry) Validate() (Engine, error) {
 		e.dbServers = make(map[string]DB)
 	}
 	e.registry.dbTables = make(map[string]map[string]bool)
+	e.registry.referenceActions = make(map[reflect.Type][]referenceAction)
 	for k, v := range r.mysqlPools {
 		if len(k) > maxPoolLen {
 			maxPoolLen = len(k)
@@ -178,6 +189,36 @@ func (r *registry) Validate() (Engine, error) {
 		if schema.hasRedisCache {
 			schema.redisCache = e.redisServers[schema.redisCacheName].(*redisCache)
 		}
+		for columnName, reference := range schema.references {
+			if reference.OnDelete != "" {
+				action := referenceAction{schema: schema, column: columnName, onDelete: reference.OnDelete}
+				e.registry.referenceActions[reference.Type] = append(e.registry.referenceActions[reference.Type], action)
+			}
+		}
+	}
+	e.asyncBuffer = &AsyncBufferOptions{}
+	if r.asyncBuffer != nil {
+		buffer := *r.asyncBuffer
+		e.asyncBuffer = &buffer
+	}
+	if e.asyncBuffer.DrainTimeout <= 0 {
+		e.asyncBuffer.DrainTimeout = asyncBufferDrainTimeout
+	}
+	switch e.asyncBuffer.Mode {
+	case AsyncBufferBlockingTimeout:
+		if e.asyncBuffer.Timeout <= 0 {
+			return nil, errors.New("async buffer timeout must be greater than zero")
+		}
+	case AsyncBufferWAL:
+		if e.asyncBuffer.WALDirectory == "" {
+			return nil, errors.New("async buffer WAL directory is not defined")
+		}
+		if err := os.MkdirAll(e.asyncBuffer.WALDirectory, 0700); err != nil {
+			return nil, err
+		}
+		for _, schema := range e.registry.entitySchemas {
+			schema.asyncBufferWAL = newAsyncBufferWAL(e.asyncBuffer.WALDirectory, schema)
+		}
 	}
 	e.registry.defaultQueryLogger = &defaultLogLogger{maxPoolLen: maxPoolLen, logger: log.New(os.Stderr, "", 0)}
 	for _, schema := range e.registry.entitySchemas {
@@ -203,6 +244,40 @@ func (r *registry) Validate() (Engine, error) {
 	for key, value := range r.options {
 		e.registry.options[key] = value
 	}
+	e.flushRetryPolicy = r.flushRetryPolicy
+	e.asyncErrorPolicy = r.asyncErrorPolicy
+	if r.asyncFlushStreams != nil {
+		streams := *r.asyncFlushStreams
+		if streams.Partitions <= 0 {
+			streams.Partitions = 1
+		}
+		if streams.ClaimMinIdle <= 0 {
+			streams.ClaimMinIdle = asyncStreamsClaimMinIdle
+		}
+		e.asyncFlushStreams = &streams
+	}
+	if r.localCacheBus != nil {
+		options := *r.localCacheBus
+		if options.RedisPool == "" {
+			options.RedisPool = DefaultPoolCode
+		}
+		if _, has := e.redisServers[options.RedisPool]; !has {
+			return nil, fmt.Errorf("local cache invalidation redis pool '%s' not found", options.RedisPool)
+		}
+		if options.Channel == "" {
+			options.Channel = localCacheInvalidationChannel
+		}
+		if options.BatchSize <= 0 {
+			options.BatchSize = localCacheInvalidationBatchSize
+		}
+		if options.BatchInterval <= 0 {
+			options.BatchInterval = localCacheInvalidationInterval
+		}
+		if options.QueueSize <= 0 {
+			options.QueueSize = localCacheInvalidationQueueSize
+		}
+		e.localCacheInvalidation = newLocalCacheInvalidationBus(&options)
+	}
 	return e, nil
 }
 
@@ -214,6 +289,26 @@ func (r *registry) SetOption(key string, value any) {
 	r.options[key] = value
 }
 
+func (r *registry) SetFlushRetryPolicy(policy *FlushRetryPolicy) {
+	r.flushRetryPolicy = policy
+}
+
+func (r *registry) SetAsyncFlushStreams(options *AsyncFlushStreamsOptions) {
+	r.asyncFlushStreams = options
+}
+
+func (r *registry) SetAsyncErrorPolicy(policy *AsyncErrorPolicy) {
+	r.asyncErrorPolicy = policy
+}
+
+func (r *registry) SetAsyncBuffer(options *AsyncBufferOptions) {
+	r.asyncBuffer = options
+}
+
+func (r *registry) SetLocalCacheInvalidation(options *LocalCacheInvalidationOptions) {
+	r.localCacheBus = options
+}
+
 func (r *registry) RegisterEntity(entity ...any) {
 	if r.entities == nil {
 		r.entities = make(map[string]reflect.Type)
@@ -248,6 +343,7 @@ type MySQLOptions struct {
 	DefaultEncoding    string
 	DefaultCollate     string
 	IgnoredTables      []string
+	MaxInsertRows      int
 }
 
 func (r *registry) RegisterMySQL(dataSourceName string, poolCode string, poolOptions *MySQLOptions) {
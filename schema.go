@@ -179,6 +179,7 @@ func getSchemaChanges(orm ORM, entitySchema *entitySchema) (preAlters, alters, p
 	indexes := make(map[string]*IndexSchemaDefinition)
 	columns, err := checkStruct(orm.Engine(), entitySchema, entitySchema.GetType(), indexes, nil, "", -1)
 	checkError(err)
+	columns = addSoftDeleteToSchema(entitySchema, columns, indexes)
 	indexesSlice := make([]*IndexSchemaDefinition, 0)
 	for _, index := range indexes {
 		indexesSlice = append(indexesSlice, index)
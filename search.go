@@ -254,6 +254,7 @@ func prepareScanForFields(fields *tableFields, start int, pointers []any) int {
 func searchRow[E any](orm ORM, where Where) (entity *E, found bool) {
 	schema := getEntitySchema[E](orm)
 	pool := schema.GetDB()
+	where = schema.excludeDeleted(where)
 	whereQuery := where.String()
 
 	if schema.hasLocalCache {
@@ -288,6 +289,7 @@ func search[E any](orm ORM, where Where, pager *Pager, withCount bool) (results
 		}
 		return &localCacheIDsIterator[E]{orm: orm.(*ormImplementation), schema: schema, ids: ids, index: -1}, total
 	}
+	where = schema.excludeDeleted(where)
 	whereQuery := where.String()
 	query := "SELECT " + schema.fieldsQuery + " FROM `" + schema.GetTableName() + "` WHERE " + whereQuery
 	if pager != nil {
@@ -321,6 +323,7 @@ func searchOne[E any](orm ORM, where Where) (*E, bool) {
 }
 
 func searchIDs(orm ORM, schema EntitySchema, where Where, pager *Pager, withCount bool) (ids []uint64, total int) {
+	where = schema.(*entitySchema).excludeDeleted(where)
 	whereQuery := where.String()
 	/* #nosec */
 	query := "SELECT `ID` FROM `" + schema.GetTableName() + "` WHERE " + whereQuery
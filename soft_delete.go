@@ -0,0 +1,147 @@
+package beeorm
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+const fakeDeleteColumn = "FakeDelete"
+
+var whereTrailingClauseRegexp = regexp.MustCompile(`^(?i)(ORDER\s+BY|GROUP\s+BY|HAVING|LIMIT)\b`)
+
+type withDeletedWhere struct {
+	Where
+}
+
+func WithDeleted(where Where) Where {
+	return &withDeletedWhere{where}
+}
+
+func (e *entitySchema) excludeDeleted(where Where) Where {
+	withDeleted, isWithDeleted := where.(*withDeletedWhere)
+	if isWithDeleted {
+		return withDeleted.Where
+	}
+	if !e.softDelete {
+		return where
+	}
+	query := where.String()
+	end := whereClausesEnd(query)
+	condition := strings.TrimSpace(query[0:end])
+	filter := "`" + fakeDeleteColumn + "` = 0"
+	if condition != "" {
+		filter += " AND (" + condition + ")"
+	}
+	if end < len(query) {
+		filter += " " + query[end:]
+	}
+	return &BaseWhere{query: filter, parameters: where.GetParameters()}
+}
+
+func whereClausesEnd(query string) int {
+	depth := 0
+	for i := 0; i < len(query); i++ {
+		switch query[i] {
+		case '\'', '"', '`':
+			i = quotedValueEnd(query, i)
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case 'O', 'o', 'G', 'g', 'H', 'h', 'L', 'l':
+			if depth > 0 || (i > 0 && !unicode.IsSpace(rune(query[i-1])) && query[i-1] != ')') {
+				continue
+			}
+			if whereTrailingClauseRegexp.MatchString(query[i:]) {
+				return i
+			}
+		}
+	}
+	return len(query)
+}
+
+func quotedValueEnd(query string, start int) int {
+	quote := query[start]
+	for i := start + 1; i < len(query); i++ {
+		switch query[i] {
+		case '\\':
+			if quote != '`' {
+				i++
+			}
+		case quote:
+			return i
+		}
+	}
+	return len(query)
+}
+
+func ForceDeleteEntity[E any](orm ORM, source E) {
+	toRemove := &removableEntity{force: true}
+	toRemove.orm = orm
+	toRemove.source = source
+	toRemove.value = reflect.ValueOf(source).Elem()
+	toRemove.id = toRemove.value.Field(0).Uint()
+	toRemove.schema = getEntitySchema[E](orm)
+	orm.trackEntity(toRemove)
+}
+
+func RestoreEntity[E any](orm ORM, source E) {
+	schema := getEntitySchema[E](orm)
+	if !schema.softDelete {
+		panic(fmt.Errorf("entity '%s' does not support soft delete", schema.t.String()))
+	}
+	restore := &insertableEntity{restore: true}
+	restore.orm = orm
+	restore.schema = schema
+	restore.entity = source
+	restore.value = reflect.ValueOf(source)
+	restore.id = restore.value.Elem().Field(0).Uint()
+	orm.trackEntity(restore)
+}
+
+func (orm *ormImplementation) handleRestore(async bool, schema *entitySchema, restore *insertableEntity) error {
+	bind, err := restore.getBind()
+	if err != nil {
+		return err
+	}
+	if async {
+		event := orm.newAsyncEvent(schema, Insert, restore.id, nil, bind)
+		event.Restore = true
+		orm.publishAsyncEventAfterFlush(schema, asyncTemporaryQueueEvent{event})
+	} else {
+		sql := "UPDATE `" + schema.GetTableName() + "` SET `" + fakeDeleteColumn + "` = 0 WHERE ID = ?"
+		orm.appendDBAction(schema, func(db DBBase) {
+			db.Exec(orm, sql, restore.id)
+		})
+		if schema.hasSubscribers() {
+			orm.appendFlushedEvents(&flushedEvent{schema: schema, flushType: Insert, id: restore.id, after: bind})
+		}
+	}
+	logTableSchema, hasLogTable := orm.engine.registry.entityLogSchemas[schema.t]
+	if hasLogTable {
+		orm.countAsyncEvents(logTableSchema, 1)
+	}
+	cacheActions := orm.refreshCacheAfterUpsert(schema, restore.id, restore.getEntity(), bind, nil, true, nil)
+	orm.flushPostActions = append(orm.flushPostActions, func(_ ORM) {
+		for _, action := range cacheActions {
+			action()
+		}
+	})
+	orm.appendAfterFlushHook(restore.getEntity(), Insert)
+	return nil
+}
+
+func addSoftDeleteToSchema(schema *entitySchema, columns []*ColumnSchemaDefinition, indexes map[string]*IndexSchemaDefinition) []*ColumnSchemaDefinition {
+	if !schema.softDelete {
+		return columns
+	}
+	for _, index := range indexes {
+		if index.Unique {
+			index.columnsMap[len(index.columnsMap)+1] = fakeDeleteColumn
+		}
+	}
+	return append(columns, &ColumnSchemaDefinition{fakeDeleteColumn, "`" + fakeDeleteColumn + "` bigint unsigned NOT NULL DEFAULT '0'"})
+}
@@ -0,0 +1,122 @@
+package beeorm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type softDeleteEntityReference struct {
+	ID uint64
+}
+
+type softDeleteEntity struct {
+	ID        uint64                               `orm:"softDelete;localCache;redisCache"`
+	Name      string                               `orm:"required;unique=Name;cached"`
+	Age       uint8                                `orm:"index=Age;cached"`
+	Reference Reference[softDeleteEntityReference] `orm:"cached"`
+}
+
+func TestSoftDeleteNoCache(t *testing.T) {
+	testSoftDelete(t, false, false)
+}
+
+func TestSoftDeleteLocalCache(t *testing.T) {
+	testSoftDelete(t, true, false)
+}
+
+func TestSoftDeleteRedis(t *testing.T) {
+	testSoftDelete(t, false, true)
+}
+
+func TestSoftDeleteLocalCacheRedis(t *testing.T) {
+	testSoftDelete(t, true, true)
+}
+
+func testSoftDelete(t *testing.T, local, redis bool) {
+	var entity *softDeleteEntity
+	var reference *softDeleteEntityReference
+	orm := PrepareTables(t, NewRegistry(), entity, reference)
+	schema := GetEntitySchema[softDeleteEntity](orm)
+	schema.DisableCache(!local, !redis)
+
+	ref := NewEntity[softDeleteEntityReference](orm)
+	entity = NewEntity[softDeleteEntity](orm)
+	entity.Name = "a"
+	entity.Age = 10
+	entity.Reference = Reference[softDeleteEntityReference](ref.ID)
+	entity2 := NewEntity[softDeleteEntity](orm)
+	entity2.Name = "b"
+	entity2.Age = 10
+	assert.NoError(t, orm.Flush())
+	id := entity.ID
+
+	/* warm up caches */
+	GetByID[softDeleteEntity](orm, id)
+	assert.Equal(t, 2, GetByIndex[softDeleteEntity](orm, "Age", 10).Len())
+	assert.Equal(t, 1, GetByReference[softDeleteEntity](orm, "Reference", ref.ID).Len())
+
+	DeleteEntity(orm, entity)
+	assert.NoError(t, orm.Flush())
+	_, found := GetByID[softDeleteEntity](orm, id)
+	assert.False(t, found)
+	_, found = GetByUniqueIndex[softDeleteEntity](orm, "Name", "a")
+	assert.False(t, found)
+	assert.Equal(t, 1, GetByIndex[softDeleteEntity](orm, "Age", 10).Len())
+	assert.Equal(t, 0, GetByReference[softDeleteEntity](orm, "Reference", ref.ID).Len())
+	assert.Equal(t, 1, GetAll[softDeleteEntity](orm).Len())
+	assert.Equal(t, 1, Search[softDeleteEntity](orm, NewWhere("1 ORDER BY ID"), nil).Len())
+	assert.Equal(t, 0, Search[softDeleteEntity](orm, NewWhere("Name = 'x limit 1' OR Name = 'a'\nORDER BY ID"), nil).Len())
+	assert.Equal(t, 2, Search[softDeleteEntity](orm, WithDeleted(NewWhere("1")), nil).Len())
+	total := 0
+	orm.Engine().DB(DefaultPoolCode).QueryRow(orm, NewWhere("SELECT COUNT(*) FROM `softDeleteEntity`"), &total)
+	assert.Equal(t, 2, total)
+
+	entity = NewEntity[softDeleteEntity](orm)
+	entity.Name = "a"
+	assert.NoError(t, orm.Flush())
+	newID := entity.ID
+	entity, found = GetByUniqueIndex[softDeleteEntity](orm, "Name", "a")
+	assert.True(t, found)
+	assert.Equal(t, newID, entity.ID)
+	ForceDeleteEntity(orm, entity)
+	assert.NoError(t, orm.Flush())
+	orm.Engine().DB(DefaultPoolCode).QueryRow(orm, NewWhere("SELECT COUNT(*) FROM `softDeleteEntity`"), &total)
+	assert.Equal(t, 2, total)
+
+	entity = &softDeleteEntity{ID: id, Name: "a", Age: 10, Reference: Reference[softDeleteEntityReference](ref.ID)}
+	RestoreEntity(orm, entity)
+	assert.NoError(t, orm.Flush())
+	entity, found = GetByID[softDeleteEntity](orm, id)
+	assert.True(t, found)
+	assert.Equal(t, "a", entity.Name)
+	entity, found = GetByUniqueIndex[softDeleteEntity](orm, "Name", "a")
+	assert.True(t, found)
+	assert.Equal(t, id, entity.ID)
+	assert.Equal(t, 2, GetByIndex[softDeleteEntity](orm, "Age", 10).Len())
+	assert.Equal(t, 1, GetByReference[softDeleteEntity](orm, "Reference", ref.ID).Len())
+
+	affected, err := DeleteWhere[softDeleteEntity](orm, NewWhere("Age = ?", 10), nil)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(2), affected)
+	assert.Equal(t, 0, GetAll[softDeleteEntity](orm).Len())
+	orm.Engine().DB(DefaultPoolCode).QueryRow(orm, NewWhere("SELECT COUNT(*) FROM `softDeleteEntity`"), &total)
+	assert.Equal(t, 2, total)
+
+	assert.PanicsWithError(t, "entity 'beeorm.softDeleteEntityReference' does not support soft delete", func() {
+		RestoreEntity(orm, ref)
+	})
+}
+
+func TestSoftDeleteWhere(t *testing.T) {
+	var entity *softDeleteEntity
+	var reference *softDeleteEntityReference
+	orm := PrepareTables(t, NewRegistry(), entity, reference)
+	schema := getEntitySchema[softDeleteEntity](orm)
+
+	assert.Equal(t, "`FakeDelete` = 0 AND (Age = ?) ORDER BY ID", schema.excludeDeleted(NewWhere("Age = ? ORDER BY ID", 1)).String())
+	assert.Equal(t, "`FakeDelete` = 0 AND (Age IN (SELECT Age FROM x LIMIT 1))", schema.excludeDeleted(NewWhere("Age IN (SELECT Age FROM x LIMIT 1)")).String())
+	assert.Equal(t, "`FakeDelete` = 0 AND (Name = 'a ORDER BY b' OR Name = \"it\\\" LIMIT 2\") limit 5", schema.excludeDeleted(NewWhere("Name = 'a ORDER BY b' OR Name = \"it\\\" LIMIT 2\"\nlimit 5")).String())
+	assert.Equal(t, "`FakeDelete` = 0 AND (`Order` = 1 OR Lorder = 2)", schema.excludeDeleted(NewWhere("`Order` = 1 OR Lorder = 2")).String())
+	assert.Equal(t, "`FakeDelete` = 0 ORDER BY ID", schema.excludeDeleted(NewWhere("ORDER BY ID")).String())
+}
@@ -0,0 +1,185 @@
+package beeorm
+
+import (
+	"fmt"
+	"maps"
+	"reflect"
+	"strconv"
+	"strings"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+type FlushedEvent[E any] struct {
+	EntityFlushedEvent
+}
+
+type flushedEventHandler func(orm ORM, event *flushedEvent)
+
+type flushedEvent struct {
+	schema    *entitySchema
+	flushType FlushType
+	id        uint64
+	before    Bind
+	after     Bind
+	meta      Meta
+}
+
+func (e *flushedEvent) FlushType() FlushType {
+	return e.flushType
+}
+
+func (e *flushedEvent) ID() uint64 {
+	return e.id
+}
+
+func (e *flushedEvent) Schema() EntitySchema {
+	return e.schema
+}
+
+func (e *flushedEvent) Before() Bind {
+	return e.before
+}
+
+func (e *flushedEvent) After() Bind {
+	return e.after
+}
+
+func (e *flushedEvent) Meta() Meta {
+	return e.meta
+}
+
+type asyncFlushedEvent struct {
+	Schema string    `json:"s"`
+	Type   FlushType `json:"t"`
+	ID     string    `json:"i"`
+	Before Bind      `json:"b,omitempty"`
+	After  Bind      `json:"a,omitempty"`
+	Meta   Meta      `json:"m,omitempty"`
+}
+
+type asyncFlushedEvents []*asyncFlushedEvent
+
+func Subscribe[E any](engine Engine, handler func(orm ORM, event FlushedEvent[E])) {
+	t := reflect.TypeOf((*E)(nil)).Elem()
+	schema, has := engine.(*engineImplementation).registry.entitySchemasQuickMap[t]
+	if !has {
+		panic(fmt.Errorf("entity '%s' is not registered", t.String()))
+	}
+	schema.subscribersMutex.Lock()
+	defer schema.subscribersMutex.Unlock()
+	schema.subscribers = append(schema.subscribers, func(orm ORM, event *flushedEvent) {
+		handler(orm, FlushedEvent[E]{event})
+	})
+}
+
+func (e *entitySchema) getSubscribers() []flushedEventHandler {
+	e.subscribersMutex.RLock()
+	defer e.subscribersMutex.RUnlock()
+	return e.subscribers
+}
+
+func (e *entitySchema) hasSubscribers() bool {
+	return len(e.getSubscribers()) > 0
+}
+
+func (orm *ormImplementation) appendFlushedEvents(events ...*flushedEvent) {
+	meta := maps.Clone(orm.meta)
+	for _, event := range events {
+		event.meta = meta
+	}
+	orm.flushPostActions = append(orm.flushPostActions, func(_ ORM) {
+		orm.dispatchFlushedEvents(events...)
+	})
+}
+
+func (orm *ormImplementation) dispatchFlushedEvents(events ...*flushedEvent) {
+	if orm.plan != nil {
+		return
+	}
+	for _, event := range events {
+		for _, handler := range event.schema.getSubscribers() {
+			handler(orm, event)
+		}
+	}
+}
+
+func encodeAsyncBind(bind Bind) Bind {
+	if bind == nil {
+		return nil
+	}
+	encoded := make(Bind, len(bind))
+	for column, value := range bind {
+		switch v := value.(type) {
+		case uint64:
+			encoded[column] = strconv.FormatUint(v, 10)
+		case int64:
+			encoded[column] = strconv.FormatInt(v, 10)
+		default:
+			encoded[column] = value
+		}
+	}
+	return encoded
+}
+
+func decodeAsyncBind(schema *entitySchema, bind Bind) Bind {
+	for column, value := range bind {
+		if column == "ID" {
+			bind[column], _ = strconv.ParseUint(fmt.Sprintf("%v", value), 10, 64)
+			continue
+		}
+		setter, has := schema.fieldBindSetters[column]
+		if !has {
+			continue
+		}
+		decoded, err := setter(value)
+		if err == nil {
+			bind[column] = decoded
+		}
+	}
+	return bind
+}
+
+func splitAsyncEvent(value string) (flushed string, event string) {
+	if !strings.HasPrefix(value, "[[") {
+		return "", value
+	}
+	var raw []jsoniter.RawMessage
+	err := jsoniter.ConfigFastest.UnmarshalFromString(value, &raw)
+	if err != nil || len(raw) == 0 {
+		return "", value
+	}
+	event = "["
+	for i, part := range raw[1:] {
+		if i > 0 {
+			event += ","
+		}
+		event += string(part)
+	}
+	return string(raw[0]), event + "]"
+}
+
+func decodeAsyncFlushedEvents(orm ORM, value string) []*flushedEvent {
+	if value == "" {
+		return nil
+	}
+	var encoded asyncFlushedEvents
+	_ = jsoniter.ConfigFastest.UnmarshalFromString(value, &encoded)
+	events := make([]*flushedEvent, 0, len(encoded))
+	for _, event := range encoded {
+		schema := orm.Engine().Registry().EntitySchema(event.Schema)
+		if schema == nil {
+			continue
+		}
+		id, _ := strconv.ParseUint(event.ID, 10, 64)
+		events = append(events, &flushedEvent{
+			schema:    schema.(*entitySchema),
+			flushType: event.Type,
+			id:        id,
+			before:    decodeAsyncBind(schema.(*entitySchema), event.Before),
+			after:     decodeAsyncBind(schema.(*entitySchema), event.After),
+			meta:      event.Meta,
+		})
+	}
+	return events
+}
@@ -0,0 +1,84 @@
+package beeorm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type subscribeEntity struct {
+	ID   uint64 `orm:"localCache;redisCache"`
+	Name string
+	Age  uint8
+}
+
+func TestSubscribe(t *testing.T) {
+	testSubscribe(t, false)
+}
+
+func TestSubscribeAsync(t *testing.T) {
+	testSubscribe(t, true)
+}
+
+func testSubscribe(t *testing.T, async bool) {
+	var entity *subscribeEntity
+	orm := PrepareTables(t, NewRegistry(), entity)
+	var events []FlushedEvent[subscribeEntity]
+	Subscribe[subscribeEntity](orm.Engine(), func(_ ORM, event FlushedEvent[subscribeEntity]) {
+		events = append(events, event)
+	})
+	flush := func() {
+		if async {
+			assert.NoError(t, orm.FlushAsync())
+			assert.Len(t, events, 0)
+			assert.NoError(t, runAsyncConsumer(orm, false))
+			return
+		}
+		assert.NoError(t, orm.Flush())
+	}
+
+	orm.SetMetaData("source", "test")
+	entity = NewEntity[subscribeEntity](orm)
+	entity.Name = "a"
+	entity.Age = 10
+	flush()
+	assert.Len(t, events, 1)
+	assert.Equal(t, Insert, events[0].FlushType())
+	assert.Equal(t, entity.ID, events[0].ID())
+	assert.Equal(t, GetEntitySchema[subscribeEntity](orm), events[0].Schema())
+	assert.Nil(t, events[0].Before())
+	assert.Equal(t, "a", events[0].After()["Name"])
+	assert.Equal(t, uint64(10), events[0].After()["Age"])
+	assert.Equal(t, entity.ID, events[0].After()["ID"])
+	assert.Equal(t, Meta{"source": "test"}, events[0].Meta())
+
+	events = nil
+	entity = EditEntity(orm, entity)
+	entity.Name = "b"
+	flush()
+	assert.Len(t, events, 1)
+	assert.Equal(t, Update, events[0].FlushType())
+	assert.Equal(t, Bind{"Name": "a"}, events[0].Before())
+	assert.Equal(t, Bind{"Name": "b"}, events[0].After())
+
+	events = nil
+	DeleteEntity(orm, entity)
+	flush()
+	assert.Len(t, events, 1)
+	assert.Equal(t, Delete, events[0].FlushType())
+	assert.Equal(t, entity.ID, events[0].ID())
+	assert.Equal(t, "b", events[0].Before()["Name"])
+	assert.Nil(t, events[0].After())
+
+	if !async {
+		events = nil
+		assert.NoError(t, orm.Transaction(func(tx ORM) error {
+			entity = NewEntity[subscribeEntity](tx)
+			entity.Name = "c"
+			assert.NoError(t, tx.Flush())
+			assert.Len(t, events, 0)
+			return nil
+		}))
+		assert.Len(t, events, 1)
+	}
+}
@@ -0,0 +1,69 @@
+package beeorm
+
+func (orm *ormImplementation) Transaction(f func(tx ORM) error) (err error) {
+	if orm.inTransaction {
+		return f(orm)
+	}
+	tx := orm.Clone().(*ormImplementation)
+	tx.inTransaction = true
+	committed := false
+	defer func() {
+		if !committed {
+			tx.rollbackTransaction()
+		}
+	}()
+	err = f(tx)
+	if err != nil {
+		return err
+	}
+	err = tx.Flush()
+	if err != nil {
+		return err
+	}
+	for _, dbTX := range tx.dbTransactions {
+		dbTX.Commit(tx)
+	}
+	committed = true
+	for _, pipeline := range tx.transactionPipeLines {
+		pipeline.Exec(tx)
+	}
+	for _, action := range tx.transactionPostActions {
+		action(tx)
+	}
+	tx.releaseAsyncBuffer(tx.asyncBufferReserved)
+	tx.transactionPipeLines = nil
+	tx.transactionPostActions = nil
+	return nil
+}
+
+func (orm *ormImplementation) getDBTransaction(db *dbImplementation) *dbImplementation {
+	orm.mutexData.Lock()
+	defer orm.mutexData.Unlock()
+	code := db.GetConfig().GetCode()
+	dbTX, has := orm.dbTransactions[code]
+	if has {
+		return dbTX.(*dbImplementation)
+	}
+	if orm.dbTransactions == nil {
+		orm.dbTransactions = make(map[string]DBTransaction)
+	}
+	dbTX = db.begin(orm)
+	orm.dbTransactions[code] = dbTX
+	return dbTX.(*dbImplementation)
+}
+
+func (orm *ormImplementation) rollbackTransaction() {
+	for _, dbTX := range orm.dbTransactions {
+		dbTX.Rollback(orm)
+	}
+	orm.dbTransactions = nil
+	orm.transactionPipeLines = nil
+	orm.transactionPostActions = nil
+	orm.releaseAsyncBuffer(orm.asyncBufferReserved)
+	if orm.trackedEntities != nil {
+		orm.trackedEntities.Clear()
+	}
+	orm.flushDBActions = nil
+	orm.flushPostActions = nil
+	orm.redisPipeLines = nil
+}
@@ -0,0 +1,190 @@
+package beeorm
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type transactionEntity struct {
+	ID   uint64 `orm:"localCache;redisCache"`
+	Name string `orm:"required;unique=Name;cached"`
+	Age  uint8
+}
+
+func TestTransactionNoCache(t *testing.T) {
+	testTransaction(t, false, false)
+}
+
+func TestTransactionLocalCache(t *testing.T) {
+	testTransaction(t, true, false)
+}
+
+func TestTransactionRedis(t *testing.T) {
+	testTransaction(t, false, true)
+}
+
+func TestTransactionLocalCacheRedis(t *testing.T) {
+	testTransaction(t, true, true)
+}
+
+func testTransaction(t *testing.T, local, redis bool) {
+	var entity *transactionEntity
+	orm := PrepareTables(t, NewRegistry(), entity)
+	schema := GetEntitySchema[transactionEntity](orm)
+	schema.DisableCache(!local, !redis)
+	db := orm.Engine().DB(DefaultPoolCode)
+
+	var id uint64
+	err := orm.Transaction(func(tx ORM) error {
+		entity = NewEntity[transactionEntity](tx)
+		entity.Name = "a"
+		id = entity.ID
+		err := tx.Flush()
+		if err != nil {
+			return err
+		}
+		db.Exec(tx, "UPDATE `transactionEntity` SET `Age` = 10 WHERE ID = ?", id)
+		var age uint8
+		found := db.QueryRow(tx, NewWhere("SELECT `Age` FROM `transactionEntity` WHERE ID = ? FOR UPDATE", id), &age)
+		assert.True(t, found)
+		assert.Equal(t, uint8(10), age)
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.True(t, db.QueryRow(orm, NewWhere("SELECT ID FROM `transactionEntity` WHERE ID = ?", id)))
+	entity, found := GetByID[transactionEntity](orm, id)
+	assert.True(t, found)
+	assert.Equal(t, "a", entity.Name)
+	entity, found = GetByUniqueIndex[transactionEntity](orm, "Name", "a")
+	assert.True(t, found)
+	assert.Equal(t, id, entity.ID)
+
+	ageBefore := entity.Age
+	rollbackError := errors.New("rollback")
+	err = orm.Transaction(func(tx ORM) error {
+		entity = NewEntity[transactionEntity](tx)
+		entity.Name = "b"
+		err := tx.Flush()
+		if err != nil {
+			return err
+		}
+		entity = EditEntity(tx, entity)
+		entity.Name = "c"
+		err = tx.Flush()
+		if err != nil {
+			return err
+		}
+		edited, _ := GetByID[transactionEntity](tx, id)
+		edited = EditEntity(tx, edited)
+		edited.Name = "d"
+		err = tx.Flush()
+		if err != nil {
+			return err
+		}
+		edited, _ = GetByID[transactionEntity](tx, id)
+		err = EditEntityField(tx, edited, "Name", "e")
+		if err != nil {
+			return err
+		}
+		err = IncrementEntityField(tx, edited, "Age", 5)
+		if err != nil {
+			return err
+		}
+		err = tx.Flush()
+		if err != nil {
+			return err
+		}
+		return rollbackError
+	})
+	assert.Equal(t, rollbackError, err)
+	entity, found = GetByID[transactionEntity](orm, id)
+	assert.True(t, found)
+	assert.Equal(t, "a", entity.Name)
+	assert.Equal(t, ageBefore, entity.Age)
+	_, found = GetByUniqueIndex[transactionEntity](orm, "Name", "c")
+	assert.False(t, found)
+	_, found = GetByUniqueIndex[transactionEntity](orm, "Name", "d")
+	assert.False(t, found)
+	entity, found = GetByUniqueIndex[transactionEntity](orm, "Name", "a")
+	assert.True(t, found)
+	assert.Equal(t, id, entity.ID)
+	total := 0
+	db.QueryRow(orm, NewWhere("SELECT COUNT(*) FROM `transactionEntity`"), &total)
+	assert.Equal(t, 1, total)
+
+	assert.PanicsWithValue(t, "test", func() {
+		_ = orm.Transaction(func(tx ORM) error {
+			db.Exec(tx, "UPDATE `transactionEntity` SET `Age` = 20 WHERE ID = ?", id)
+			panic("test")
+		})
+	})
+	var age uint8
+	db.QueryRow(orm, NewWhere("SELECT `Age` FROM `transactionEntity` WHERE ID = ?", id), &age)
+	assert.Equal(t, uint8(10), age)
+}
+
+func TestTransactionSavepoints(t *testing.T) {
+	var entity *transactionEntity
+	orm := PrepareTables(t, NewRegistry(), entity)
+	db := orm.Engine().DB(DefaultPoolCode)
+	loggerDB := &MockLogHandler{}
+	orm.RegisterQueryLogger(loggerDB, true, false, false)
+
+	tx := db.Begin(orm)
+	tx.Exec(orm, "INSERT INTO `transactionEntity`(`ID`, `Name`, `Age`) VALUES(1, 'a', 1)")
+	tx.Savepoint(orm, "first")
+	tx.Exec(orm, "INSERT INTO `transactionEntity`(`ID`, `Name`, `Age`) VALUES(2, 'b', 1)")
+	tx.RollbackTo(orm, "first")
+	tx.Release(orm, "first")
+	assert.PanicsWithError(t, "invalid savepoint name 'first`; DROP TABLE `transactionEntity'", func() {
+		tx.Savepoint(orm, "first`; DROP TABLE `transactionEntity")
+	})
+
+	nested := tx.Begin(orm)
+	nested.Exec(orm, "INSERT INTO `transactionEntity`(`ID`, `Name`, `Age`) VALUES(3, 'c', 1)")
+	nested.Rollback(orm)
+	nested = tx.Begin(orm)
+	nested.Exec(orm, "INSERT INTO `transactionEntity`(`ID`, `Name`, `Age`) VALUES(4, 'd', 1)")
+	nested.Commit(orm)
+	tx.Commit(orm)
+
+	var ids []uint64
+	rows, closeRows := db.Query(orm, "SELECT `ID` FROM `transactionEntity` ORDER BY `ID`")
+	for rows.Next() {
+		var id uint64
+		rows.Scan(&id)
+		ids = append(ids, id)
+	}
+	closeRows()
+	assert.Equal(t, []uint64{1, 4}, ids)
+	var queries []string
+	for _, log := range loggerDB.Logs {
+		if log["operation"] == "TRANSACTION" {
+			queries = append(queries, log["query"].(string))
+		}
+	}
+	assert.Equal(t, []string{
+		"START TRANSACTION",
+		"SAVEPOINT `first`",
+		"ROLLBACK TO SAVEPOINT `first`",
+		"RELEASE SAVEPOINT `first`",
+		"SAVEPOINT `beeorm_savepoint_1`",
+		"ROLLBACK TO SAVEPOINT `beeorm_savepoint_1`",
+		"SAVEPOINT `beeorm_savepoint_2`",
+		"RELEASE SAVEPOINT `beeorm_savepoint_2`",
+		"COMMIT",
+	}, queries)
+
+	err := orm.Transaction(func(tx ORM) error {
+		db.Exec(tx, "INSERT INTO `transactionEntity`(`ID`, `Name`, `Age`) VALUES(5, 'e', 1)")
+		inner := db.Begin(tx)
+		inner.Exec(tx, "INSERT INTO `transactionEntity`(`ID`, `Name`, `Age`) VALUES(6, 'f', 1)")
+		inner.Rollback(tx)
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.True(t, db.QueryRow(orm, NewWhere("SELECT ID FROM `transactionEntity` WHERE ID = 5")))
+	assert.False(t, db.QueryRow(orm, NewWhere("SELECT ID FROM `transactionEntity` WHERE ID = 6")))
+}
@@ -0,0 +1,323 @@
+package beeorm
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+type upsertData struct {
+	onDuplicate []string
+	oldBind     Bind
+	newBind     Bind
+}
+
+func UpsertEntity[E any](orm ORM, entity *E, onDuplicateFields ...string) {
+	schema := getEntitySchema[E](orm)
+	if len(schema.uniqueIndexes) == 0 {
+		panic(fmt.Errorf("entity '%s' has no unique indexes", schema.t.String()))
+	}
+	id := reflect.ValueOf(entity).Elem().Field(0).Uint()
+	var insert *insertableEntity
+	ormImpl := orm.(*ormImplementation)
+	if ormImpl.trackedEntities != nil {
+		entities, has := ormImpl.trackedEntities.Load(schema.index)
+		if has {
+			tracked, _ := entities.Load(id)
+			insert, _ = tracked.(*insertableEntity)
+		}
+	}
+	if insert == nil {
+		panic(fmt.Errorf("entity '%s' with ID %d is not a new entity", schema.t.String(), id))
+	}
+	uniqueColumns := make(map[string]bool)
+	for _, definition := range schema.uniqueIndexes {
+		for _, column := range definition.Columns {
+			uniqueColumns[column] = true
+		}
+	}
+	if len(onDuplicateFields) == 0 {
+		for _, column := range schema.columnNames[1:] {
+			if !uniqueColumns[column] && column != schema.versionColumn {
+				onDuplicateFields = append(onDuplicateFields, column)
+			}
+		}
+	}
+	for _, field := range onDuplicateFields {
+		_, has := schema.columnMapping[field]
+		if !has || field == "ID" || field == schema.versionColumn {
+			panic(fmt.Errorf("invalid on duplicate field '%s'", field))
+		}
+		if uniqueColumns[field] {
+			panic(fmt.Errorf("unique index field '%s' can't be updated on duplicate", field))
+		}
+	}
+	insert.upsert = &upsertData{onDuplicate: onDuplicateFields}
+}
+
+func (orm *ormImplementation) handleUpserts(async bool, schema *entitySchema, operations []EntityFlush) ([]EntityFlush, error) {
+	var inserts []EntityFlush
+	var invalid []*FieldValidationError
+	for _, operation := range operations {
+		insert := operation.(entityFlushInsert)
+		upsert, isUpsert := insert.(*insertableEntity)
+		if isUpsert && upsert.restore {
+			err := orm.handleRestore(async, schema, upsert)
+			if err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if !isUpsert || upsert.upsert == nil {
+			inserts = append(inserts, operation)
+			continue
+		}
+		if async {
+			return nil, fmt.Errorf("upsert of '%s' is not supported in async flush", schema.t.String())
+		}
+		err := orm.handleUpsert(schema, upsert)
+		if err != nil && !collectValidationErrors(&invalid, err) {
+			return nil, err
+		}
+	}
+	return inserts, newValidationError(invalid)
+}
+
+func (orm *ormImplementation) handleUpsert(schema *entitySchema, upsert *insertableEntity) error {
+	bind, err := upsert.getBind()
+	if err == nil {
+		err = upsert.validate(bind)
+	}
+	if err != nil {
+		return err
+	}
+	if len(orm.engine.pluginFlush) > 0 {
+		elem := upsert.getValue().Elem()
+		for _, p := range orm.engine.pluginFlush {
+			after, err := p.EntityFlush(schema, elem, nil, bind, orm.engine)
+			if err != nil {
+				return err
+			}
+			if after != nil && orm.plan == nil {
+				orm.flushPostActions = append(orm.flushPostActions, after)
+			}
+		}
+	}
+	columns := schema.GetColumns()
+	args := make([]any, len(columns))
+	sql := "INSERT INTO `" + schema.GetTableName() + "`(`ID`"
+	args[0] = bind["ID"]
+	for i, column := range columns[1:] {
+		sql += ",`" + column + "`"
+		args[i+1] = bind[column]
+	}
+	sql += ") VALUES(?" + strings.Repeat(",?", len(columns)-1) + ") ON DUPLICATE KEY UPDATE `ID` = LAST_INSERT_ID(`ID`)"
+	for _, column := range upsert.upsert.onDuplicate {
+		sql += ",`" + column + "` = VALUES(`" + column + "`)"
+	}
+	if schema.versionColumn != "" {
+		sql += ",`" + schema.versionColumn + "` = `" + schema.versionColumn + "` + 1"
+	}
+	logTableSchema, hasLogTable := orm.engine.registry.entityLogSchemas[schema.t]
+	if hasLogTable {
+		orm.countAsyncEvents(logTableSchema, 1)
+	}
+	loadPrevious := hasLogTable || len(schema.cachedIndexes) > 0 || len(schema.cachedReferences) > 0
+	var cacheActions []func()
+	upsertRow := func(db DBBase) {
+		previous := make(map[uint64]Bind)
+		if loadPrevious {
+			for _, definition := range schema.uniqueIndexes {
+				attributes := make([]any, len(definition.Columns))
+				for i, column := range definition.Columns {
+					attributes[i] = bind[column]
+				}
+				if slicesContainsNil(attributes) {
+					continue
+				}
+				// FlushPlan only inspects the flush so it must not lock rows
+				_, old, found := loadUpsertRow(orm, db, schema, NewWhere(definition.Where, attributes...), orm.plan == nil)
+				if found {
+					previous[old["ID"].(uint64)] = old
+				}
+			}
+		}
+		res := db.Exec(orm, sql, args...)
+		if res.RowsAffected() == 1 {
+			orm.appendDBCommitAction(func() {
+				cacheActions = orm.refreshCacheAfterUpsert(schema, upsert.id, upsert.getEntity(), bind, nil, true, nil)
+			})
+			return
+		}
+		id := res.LastInsertId()
+		value, final, _ := loadUpsertRow(orm, db, schema, NewWhere("`ID` = ?", id), false)
+		copyEntity(value.Elem(), upsert.value.Elem(), schema.fields, true)
+		upsert.id = id
+		upsert.upsert.newBind = final
+		upsert.upsert.oldBind = previous[id]
+		oldBind := previous[id]
+		orm.appendDBCommitAction(func() {
+			cacheActions = orm.refreshCacheAfterUpsert(schema, id, upsert.getEntity(), final, oldBind, false, upsert.upsert.onDuplicate)
+		})
+	}
+	orm.appendDBAction(schema, func(db DBBase) {
+		plain, isDB := db.(DB)
+		if !loadPrevious || !isDB {
+			upsertRow(db)
+			return
+		}
+		tx := plain.Begin(orm)
+		defer tx.Rollback(orm)
+		upsertRow(tx)
+		tx.Commit(orm)
+	})
+	orm.flushPostActions = append(orm.flushPostActions, func(_ ORM) {
+		for _, action := range cacheActions {
+			action()
+		}
+		if !schema.hasSubscribers() {
+			return
+		}
+		event := &flushedEvent{schema: schema, id: upsert.id, meta: orm.meta}
+		if upsert.upsert.newBind == nil {
+			event.flushType = Insert
+			event.after = bind
+		} else {
+			event.flushType = Update
+			event.before = upsert.upsert.oldBind
+			event.after = upsert.upsert.newBind
+		}
+		orm.dispatchFlushedEvents(event)
+	})
+	orm.appendAfterFlushHook(upsert.getEntity(), Insert)
+	return nil
+}
+
+func (orm *ormImplementation) refreshCacheAfterUpsert(schema *entitySchema, id uint64, entity any, bind, oldBind Bind, inserted bool, changedColumns []string) []func() {
+	var localActions []func()
+	idAsString := strconv.FormatUint(id, 10)
+	hasLocalCache := schema.hasLocalCache && orm.plan == nil
+	if hasLocalCache {
+		localActions = append(localActions, func() {
+			schema.localCache.updateEntity(orm, id, entity)
+		})
+	}
+	removeList := func(key string, id uint64) {
+		if hasLocalCache {
+			localActions = append(localActions, func() {
+				schema.localCache.removeList(orm, key, id)
+			})
+		}
+	}
+	if schema.hasRedisCache {
+		p := orm.RedisPipeLine(schema.redisCache.GetCode())
+		key := schema.getCacheKey() + ":" + idAsString
+		p.Del(key)
+		p.RPush(key, convertBindToRedisValue(bind, schema)...)
+		schema.setRedisCacheTTL(p, key)
+	}
+	p := orm.RedisPipeLine(schema.getForcedRedisCode())
+	for indexName, definition := range schema.cachedUniqueIndexes {
+		hField, hasKey := buildUniqueKeyHSetField(schema, definition.Columns, bind, nil)
+		if hasKey {
+			p.HSet(schema.getCacheKey()+":"+indexName, hField, idAsString)
+		}
+	}
+	for columnName := range schema.cachedReferences {
+		newID, _ := bind[columnName].(uint64)
+		oldID := uint64(0)
+		if oldBind != nil {
+			oldID, _ = oldBind[columnName].(uint64)
+		}
+		if !inserted && newID == oldID {
+			continue
+		}
+		if oldID > 0 {
+			removeList(columnName, oldID)
+			p.SRem(schema.cacheKey+":"+columnName+":"+strconv.FormatUint(oldID, 10), idAsString)
+		}
+		if newID > 0 {
+			removeList(columnName, newID)
+			redisSetKey := schema.cacheKey + ":" + columnName + ":" + strconv.FormatUint(newID, 10)
+			p.SAdd(redisSetKey, idAsString)
+			schema.setRedisCacheTTL(p, redisSetKey)
+		}
+	}
+	if inserted && schema.cacheAll {
+		removeList(cacheAllFakeReferenceKey, 0)
+		p.SAdd(schema.cacheKey+":"+cacheAllFakeReferenceKey, idAsString)
+		schema.setRedisCacheTTL(p, schema.cacheKey+":"+cacheAllFakeReferenceKey)
+	}
+	for indexName, definition := range schema.cachedIndexes {
+		attributes := make([]any, len(definition.Columns))
+		for i, column := range definition.Columns {
+			attributes[i] = bind[column]
+		}
+		newHash := hashIndexAttributes(attributes)
+		if oldBind != nil {
+			for i, column := range definition.Columns {
+				attributes[i] = oldBind[column]
+			}
+			oldHash := hashIndexAttributes(attributes)
+			if oldHash == newHash {
+				continue
+			}
+			removeList(indexName, oldHash)
+			p.SRem(schema.cacheKey+":"+indexName+":"+strconv.FormatUint(oldHash, 10), idAsString)
+		} else if !inserted && !slicesContainsAny(changedColumns, definition.Columns) {
+			continue
+		}
+		removeList(indexName, newHash)
+		redisSetKey := schema.cacheKey + ":" + indexName + ":" + strconv.FormatUint(newHash, 10)
+		p.SAdd(redisSetKey, idAsString)
+		schema.setRedisCacheTTL(p, redisSetKey)
+	}
+	logTableSchema, hasLogTable := orm.engine.registry.entityLogSchemas[schema.t]
+	if hasLogTable {
+		logEvent := orm.newLogTableEvent(schema, logTableSchema, id, oldBind, bind)
+		localActions = append(localActions, func() {
+			orm.enqueueAsyncEvent(logTableSchema, logEvent)
+		})
+	}
+	return localActions
+}
+
+func loadUpsertRow(orm ORM, db DBBase, schema *entitySchema, where Where, forUpdate bool) (value reflect.Value, bind Bind, found bool) {
+	where = schema.excludeDeleted(where)
+	/* #nosec */
+	query := "SELECT " + schema.fieldsQuery + " FROM `" + schema.GetTableName() + "` WHERE " + where.String() + " LIMIT 1"
+	if forUpdate {
+		query += " FOR UPDATE"
+	}
+	pointers := prepareScan(schema)
+	if !db.QueryRow(orm, NewWhere(query, where.GetParameters()...), pointers...) {
+		return value, nil, false
+	}
+	value = reflect.New(schema.t)
+	deserializeFromDB(schema.fields, value.Elem(), pointers)
+	bind = Bind{}
+	err := fillBindFromOneSource(orm, bind, value.Elem(), schema.fields, "")
+	checkError(err)
+	bind["ID"] = value.Elem().Field(0).Uint()
+	return value, bind, true
+}
+
+func slicesContainsNil(values []any) bool {
+	for _, value := range values {
+		if value == nil {
+			return true
+		}
+	}
+	return false
+}
+
+func slicesContainsAny(values []string, search []string) bool {
+	for _, value := range search {
+		if slices.Contains(values, value) {
+			return true
+		}
+	}
+	return false
+}
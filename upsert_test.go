@@ -0,0 +1,133 @@
+package beeorm
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type upsertEntity struct {
+	ID    uint64 `orm:"localCache;redisCache"`
+	Name  string `orm:"required;unique=Name;cached"`
+	Age   uint8  `orm:"index=Age;cached"`
+	Color string
+}
+
+func TestUpsertNoCache(t *testing.T) {
+	testUpsert(t, false, false)
+}
+
+func TestUpsertLocalCache(t *testing.T) {
+	testUpsert(t, true, false)
+}
+
+func TestUpsertRedis(t *testing.T) {
+	testUpsert(t, false, true)
+}
+
+func TestUpsertLocalCacheRedis(t *testing.T) {
+	testUpsert(t, true, true)
+}
+
+func testUpsert(t *testing.T, local, redis bool) {
+	var entity *upsertEntity
+	orm := PrepareTables(t, NewRegistry(), entity)
+	schema := GetEntitySchema[upsertEntity](orm)
+	schema.DisableCache(!local, !redis)
+
+	entity = NewEntity[upsertEntity](orm)
+	entity.Name = "a"
+	entity.Age = 10
+	entity.Color = "red"
+	UpsertEntity(orm, entity)
+	assert.NoError(t, orm.Flush())
+	id := entity.ID
+	entity, found := GetByUniqueIndex[upsertEntity](orm, "Name", "a")
+	assert.True(t, found)
+	assert.Equal(t, id, entity.ID)
+	assert.Equal(t, 1, GetByIndex[upsertEntity](orm, "Age", 10).Len())
+
+	entity = NewEntity[upsertEntity](orm)
+	entity.Name = "a"
+	entity.Age = 20
+	entity.Color = "blue"
+	UpsertEntity(orm, entity, "Age")
+	assert.NoError(t, orm.Flush())
+	assert.Equal(t, id, entity.ID)
+	assert.Equal(t, uint8(20), entity.Age)
+	assert.Equal(t, "red", entity.Color)
+
+	entity, found = GetByID[upsertEntity](orm, id)
+	assert.True(t, found)
+	assert.Equal(t, uint8(20), entity.Age)
+	assert.Equal(t, "red", entity.Color)
+	entity, found = GetByUniqueIndex[upsertEntity](orm, "Name", "a")
+	assert.True(t, found)
+	assert.Equal(t, id, entity.ID)
+	assert.Equal(t, 0, GetByIndex[upsertEntity](orm, "Age", 10).Len())
+	assert.Equal(t, 1, GetByIndex[upsertEntity](orm, "Age", 20).Len())
+	total := 0
+	orm.Engine().DB(DefaultPoolCode).QueryRow(orm, NewWhere("SELECT COUNT(*) FROM `upsertEntity`"), &total)
+	assert.Equal(t, 1, total)
+
+	entity = NewEntity[upsertEntity](orm)
+	entity.Name = "a"
+	assert.PanicsWithError(t, "unique index field 'Name' can't be updated on duplicate", func() {
+		UpsertEntity(orm, entity, "Name")
+	})
+	UpsertEntity(orm, entity)
+	assert.EqualError(t, orm.FlushAsync(), "upsert of 'beeorm.upsertEntity' is not supported in async flush")
+	orm.ClearFlush()
+}
+
+func TestUpsertLocking(t *testing.T) {
+	var entity *upsertEntity
+	orm := PrepareTables(t, NewRegistry(), entity)
+	schema := getEntitySchema[upsertEntity](orm)
+
+	entity = NewEntity[upsertEntity](orm)
+	entity.Name = "a"
+	entity.Age = 10
+	UpsertEntity(orm, entity)
+	assert.NoError(t, orm.Flush())
+
+	loggerDB := &MockLogHandler{}
+	orm.RegisterQueryLogger(loggerDB, true, false, false)
+	entity = NewEntity[upsertEntity](orm)
+	entity.Name = "a"
+	entity.Age = 20
+	UpsertEntity(orm, entity, "Age")
+	assert.NoError(t, orm.Flush())
+	assert.Equal(t, "START TRANSACTION", loggerDB.Logs[0]["query"])
+	assert.Contains(t, loggerDB.Logs[1]["query"], "FOR UPDATE")
+	assert.Contains(t, loggerDB.Logs[2]["query"], "ON DUPLICATE KEY UPDATE")
+	assert.Equal(t, "COMMIT", loggerDB.Logs[4]["query"])
+
+	entity = NewEntity[upsertEntity](orm)
+	entity.Name = "b"
+	entity.Age = 30
+	UpsertEntity(orm, entity)
+	loggerDB.Clear()
+	plan, err := orm.FlushPlan()
+	assert.NoError(t, err)
+	assert.NotEmpty(t, plan.RedisCommands)
+	for _, log := range loggerDB.Logs {
+		assert.NotContains(t, log["query"], "FOR UPDATE")
+	}
+	orm.ClearFlush()
+
+	key := schema.cacheKey + ":Age:" + strconv.FormatUint(hashIndexAttributes([]any{uint8(40)}), 10)
+	err = orm.Transaction(func(tx ORM) error {
+		entity = NewEntity[upsertEntity](tx)
+		entity.Name = "c"
+		entity.Age = 40
+		UpsertEntity(tx, entity)
+		assert.NoError(t, tx.Flush())
+		assert.Len(t, orm.Engine().Redis(DefaultPoolCode).SMembers(orm, key), 0)
+		return errors.New("rollback")
+	})
+	assert.EqualError(t, err, "rollback")
+	assert.Len(t, orm.Engine().Redis(DefaultPoolCode).SMembers(orm, key), 0)
+}
@@ -0,0 +1,243 @@
+package beeorm
+
+import (
+	"fmt"
+	"net/mail"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+var validationTags = []string{"min", "max", "regex", "email"}
+
+type EntityValidator interface {
+	Validate(orm ORM) error
+}
+
+type FieldValidationError struct {
+	Schema  EntitySchema
+	ID      uint64
+	Field   string
+	Message string
+}
+
+func (e *FieldValidationError) Error() string {
+	message := e.Message
+	if e.Field != "" {
+		message = "[" + e.Field + "] " + message
+	}
+	if e.Schema == nil {
+		return message
+	}
+	return e.Schema.GetType().String() + " with ID " + strconv.FormatUint(e.ID, 10) + ": " + message
+}
+
+type ValidationError struct {
+	Errors []*FieldValidationError
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, len(e.Errors))
+	for i, fieldError := range e.Errors {
+		messages[i] = fieldError.Error()
+	}
+	return "validation failed: " + strings.Join(messages, "; ")
+}
+
+type bindValidator func(value any) string
+
+func (e *entitySchema) initValidators() error {
+	e.hasValidateMethod = reflect.PointerTo(e.t).Implements(reflect.TypeOf((*EntityValidator)(nil)).Elem())
+	e.validators = make(map[string][]bindValidator)
+	zero := reflect.New(e.t).Elem()
+	for _, column := range e.columnNames {
+		tags, has := e.tags[column]
+		if !has {
+			if pos := strings.LastIndex(column, "_"); pos > 0 {
+				if _, err := strconv.Atoi(column[pos+1:]); err == nil {
+					tags = e.tags[column[0:pos]]
+				}
+			}
+		}
+		for _, tag := range validationTags {
+			tagValue, has := tags[tag]
+			if !has {
+				continue
+			}
+			validator, err := e.createValidator(column, tag, tagValue, zero)
+			if err != nil {
+				return err
+			}
+			e.validators[column] = append(e.validators[column], validator)
+		}
+	}
+	return nil
+}
+
+func (e *entitySchema) createValidator(column, tag, tagValue string, zero reflect.Value) (bindValidator, error) {
+	t := reflect.TypeOf(e.fieldGetters[column](zero))
+	if t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	isNumber := false
+	isString := false
+	if t != nil {
+		switch t.Kind() {
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+			reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Float32, reflect.Float64:
+			_, isReference := e.references[column]
+			isNumber = !isReference
+		case reflect.String:
+			isString = true
+		}
+	}
+	switch tag {
+	case "min", "max":
+		limit, err := strconv.ParseFloat(tagValue, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s value '%s' in %s field %s", tag, tagValue, e.t.String(), column)
+		}
+		if !isNumber && !isString {
+			break
+		}
+		return func(value any) string {
+			var asFloat float64
+			if isNumber {
+				asFloat = bindValueToFloat(value)
+			} else {
+				asFloat = float64(utf8.RuneCountInString(value.(string)))
+			}
+			if tag == "min" && asFloat < limit {
+				if isString {
+					return "must have at least " + tagValue + " characters"
+				}
+				return "must be at least " + tagValue
+			} else if tag == "max" && asFloat > limit {
+				if isString {
+					return "must have at most " + tagValue + " characters"
+				}
+				return "must be at most " + tagValue
+			}
+			return ""
+		}, nil
+	case "regex":
+		if !isString {
+			break
+		}
+		r, err := regexp.Compile(tagValue)
+		if err != nil {
+			return nil, fmt.Errorf("invalid regex '%s' in %s field %s: %w", tagValue, e.t.String(), column, err)
+		}
+		return func(value any) string {
+			if !r.MatchString(value.(string)) {
+				return "must match " + tagValue
+			}
+			return ""
+		}, nil
+	case "email":
+		if !isString {
+			break
+		}
+		return func(value any) string {
+			address, err := mail.ParseAddress(value.(string))
+			if err != nil || address.Address != value.(string) {
+				return "must be a valid email address"
+			}
+			return ""
+		}, nil
+	}
+	return nil, fmt.Errorf("%s validation is not supported in %s field %s", tag, e.t.String(), column)
+}
+
+func bindValueToFloat(value any) float64 {
+	switch v := value.(type) {
+	case uint64:
+		return float64(v)
+	case int64:
+		return float64(v)
+	case string:
+		asFloat, _ := strconv.ParseFloat(v, 64)
+		return asFloat
+	}
+	return 0
+}
+
+func (e *entitySchema) validate(orm ORM, id uint64, bind Bind, entity any) *ValidationError {
+	var errors []*FieldValidationError
+	for column, value := range bind {
+		if value == nil {
+			continue
+		}
+		for _, validator := range e.validators[column] {
+			message := validator(value)
+			if message != "" {
+				errors = append(errors, &FieldValidationError{Schema: e, ID: id, Field: column, Message: message})
+			}
+		}
+	}
+	if e.hasValidateMethod && entity != nil {
+		err := entity.(EntityValidator).Validate(orm)
+		switch asErr := err.(type) {
+		case nil:
+		case *ValidationError:
+			for _, fieldError := range asErr.Errors {
+				errors = append(errors, &FieldValidationError{Schema: e, ID: id, Field: fieldError.Field, Message: fieldError.Message})
+			}
+		case *FieldValidationError:
+			errors = append(errors, &FieldValidationError{Schema: e, ID: id, Field: asErr.Field, Message: asErr.Message})
+		case *BindError:
+			errors = append(errors, &FieldValidationError{Schema: e, ID: id, Field: asErr.Field, Message: asErr.Message})
+		default:
+			errors = append(errors, &FieldValidationError{Schema: e, ID: id, Message: err.Error()})
+		}
+	}
+	if len(errors) == 0 {
+		return nil
+	}
+	return &ValidationError{Errors: errors}
+}
+
+func (e *entitySchema) validateFields(orm ORM, f *editableFields) *ValidationError {
+	if len(e.validators) == 0 && !e.hasValidateMethod {
+		return nil
+	}
+	var entity any
+	if e.hasValidateMethod {
+		value := reflect.New(e.t)
+		copyEntity(f.value.Elem(), value.Elem(), e.fields, true)
+		for column, v := range f.newBind {
+			e.fieldSetters[column](v, value.Elem())
+		}
+		entity = value.Interface()
+	}
+	return e.validate(orm, f.id, f.newBind, entity)
+}
+
+func collectValidationErrors(collected *[]*FieldValidationError, err error) bool {
+	validationError, isValidationError := err.(*ValidationError)
+	if !isValidationError {
+		return false
+	}
+	*collected = append(*collected, validationError.Errors...)
+	return true
+}
+
+func newValidationError(collected []*FieldValidationError) error {
+	if len(collected) == 0 {
+		return nil
+	}
+	sort.Slice(collected, func(i, j int) bool {
+		if collected[i].Schema != collected[j].Schema {
+			return collected[i].Schema.GetType().String() < collected[j].Schema.GetType().String()
+		}
+		if collected[i].ID != collected[j].ID {
+			return collected[i].ID < collected[j].ID
+		}
+		return collected[i].Field < collected[j].Field
+	})
+	return &ValidationError{Errors: collected}
+}
@@ -0,0 +1,147 @@
+package beeorm
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type validationEntity struct {
+	ID    uint64  `orm:"localCache;redisCache"`
+	Name  string  `orm:"min=2;max=5;regex=^[a-z]+$"`
+	Email string  `orm:"email"`
+	Age   uint8   `orm:"max=120"`
+	Score *int16  `orm:"min=-10;max=10"`
+	Price float64 `orm:"min=0.5"`
+	Code  string  `orm:"regex=^[a-z]+\\;[0-9]+$"`
+}
+
+var validationEntityCalls int
+
+func (e *validationEntity) Validate(_ ORM) error {
+	validationEntityCalls++
+	if e.Name == "admin" {
+		return errors.New("admin is reserved")
+	}
+	return nil
+}
+
+func TestValidationNoCache(t *testing.T) {
+	testValidation(t, false, false)
+}
+
+func TestValidationLocalCache(t *testing.T) {
+	testValidation(t, true, false)
+}
+
+func TestValidationRedis(t *testing.T) {
+	testValidation(t, false, true)
+}
+
+func TestValidationLocalCacheRedis(t *testing.T) {
+	testValidation(t, true, true)
+}
+
+func testValidation(t *testing.T, local, redis bool) {
+	var entity *validationEntity
+	orm := PrepareTables(t, NewRegistry(), entity)
+	schema := GetEntitySchema[validationEntity](orm)
+	schema.DisableCache(!local, !redis)
+
+	entity = NewEntity[validationEntity](orm)
+	entity.Name = "John"
+	entity.Email = "john"
+	entity.Age = 130
+	score := int16(11)
+	entity.Score = &score
+	entity.Price = 0.2
+	entity2 := NewEntity[validationEntity](orm)
+	entity2.Name = "a"
+	entity2.Price = 1
+	err := orm.Flush()
+	validationError, isValidationError := err.(*ValidationError)
+	assert.True(t, isValidationError)
+	assert.Len(t, validationError.Errors, 6)
+	assert.Equal(t, fmt.Sprintf("validation failed: "+
+		"beeorm.validationEntity with ID %d: [Age] must be at most 120; "+
+		"beeorm.validationEntity with ID %d: [Email] must be a valid email address; "+
+		"beeorm.validationEntity with ID %d: [Name] must match ^[a-z]+$; "+
+		"beeorm.validationEntity with ID %d: [Price] must be at least 0.5; "+
+		"beeorm.validationEntity with ID %d: [Score] must be at most 10; "+
+		"beeorm.validationEntity with ID %d: [Name] must have at least 2 characters",
+		entity.ID, entity.ID, entity.ID, entity.ID, entity.ID, entity2.ID), err.Error())
+	total := 0
+	orm.Engine().DB(DefaultPoolCode).QueryRow(orm, NewWhere("SELECT COUNT(*) FROM `validationEntity`"), &total)
+	assert.Equal(t, 0, total)
+
+	entity.Name = "john"
+	entity.Email = "john@example.com"
+	entity.Age = 30
+	score = -10
+	entity.Price = 0.5
+	entity.Code = "ab;12"
+	entity2.Name = "admin"
+	err = orm.Flush()
+	assert.EqualError(t, err, fmt.Sprintf("validation failed: beeorm.validationEntity with ID %d: admin is reserved", entity2.ID))
+	entity2.Name = "tom"
+	assert.NoError(t, orm.Flush())
+
+	entity = EditEntity(orm, entity)
+	entity.Age = 121
+	calls := validationEntityCalls
+	_, newValues, isDirty := IsDirty[validationEntity](orm, entity.ID)
+	assert.True(t, isDirty)
+	assert.Equal(t, uint64(121), newValues["Age"])
+	assert.Equal(t, calls, validationEntityCalls)
+	assert.EqualError(t, orm.Flush(), fmt.Sprintf("validation failed: beeorm.validationEntity with ID %d: [Age] must be at most 120", entity.ID))
+	orm.ClearFlush()
+
+	entity, _ = GetByID[validationEntity](orm, entity.ID)
+	assert.NoError(t, EditEntityField(orm, entity, "Name", "toolong"))
+	assert.EqualError(t, orm.Flush(), fmt.Sprintf("validation failed: beeorm.validationEntity with ID %d: [Name] must have at most 5 characters", entity.ID))
+	orm.ClearFlush()
+	assert.NoError(t, EditEntityField(orm, entity, "Name", "admin"))
+	assert.EqualError(t, orm.Flush(), fmt.Sprintf("validation failed: beeorm.validationEntity with ID %d: admin is reserved", entity.ID))
+	orm.ClearFlush()
+	assert.NoError(t, EditEntityField(orm, entity, "Code", "ab12"))
+	assert.EqualError(t, orm.Flush(), fmt.Sprintf("validation failed: beeorm.validationEntity with ID %d: [Code] must match ^[a-z]+;[0-9]+$", entity.ID))
+	orm.ClearFlush()
+	entity, _ = GetByID[validationEntity](orm, entity.ID)
+	assert.Equal(t, "john", entity.Name)
+	assert.Equal(t, uint8(30), entity.Age)
+	assert.Equal(t, "ab;12", entity.Code)
+
+	entity = EditEntity(orm, entity)
+	entity.Age = 121
+	entity3 := NewEntity[validationEntity](orm)
+	entity3.Name = "a"
+	entity3.Price = 1
+	err = orm.Flush()
+	validationError, isValidationError = err.(*ValidationError)
+	assert.True(t, isValidationError)
+	assert.Len(t, validationError.Errors, 2)
+	orm.ClearFlush()
+	total = 0
+	orm.Engine().DB(DefaultPoolCode).QueryRow(orm, NewWhere("SELECT COUNT(*) FROM `validationEntity`"), &total)
+	assert.Equal(t, 2, total)
+}
+
+func TestValidationSplitTag(t *testing.T) {
+	assert.Equal(t, []string{"min=2", "regex=^[a-z]+;[0-9]+$", "max=5"}, splitTag(`min=2;regex=^[a-z]+\;[0-9]+$;max=5`))
+	assert.Equal(t, []string{"regex=^\\d+$"}, splitTag(`regex=^\d+$`))
+}
+
+type invalidValidationEntity struct {
+	ID     uint64
+	Active bool `orm:"email"`
+}
+
+func TestValidationInvalidTag(t *testing.T) {
+	registry := NewRegistry()
+	registry.RegisterMySQL("root:root@tcp(localhost:3377)/test", DefaultPoolCode, &MySQLOptions{})
+	registry.RegisterEntity(&invalidValidationEntity{})
+	_, err := registry.Validate()
+	assert.EqualError(t, err, "email validation is not supported in beeorm.invalidValidationEntity field Active")
+}
@@ -0,0 +1,449 @@
+package beeorm
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+const verifyCacheChunkSize = 1000
+
+const (
+	CacheReportRedisEntity = "redis entity"
+	CacheReportLocalEntity = "local entity"
+	CacheReportUniqueIndex = "unique index"
+	CacheReportSet         = "set"
+)
+
+type CacheReportEntry struct {
+	Cache string
+	Key   string
+	Field string
+	ID    uint64
+}
+
+type CacheReport struct {
+	Checked  uint64
+	Missing  []CacheReportEntry
+	Stale    []CacheReportEntry
+	Orphaned []CacheReportEntry
+	Repaired uint64
+}
+
+type cacheVerifierSet struct {
+	name    string
+	columns []string
+}
+
+type cacheVerifierRow struct {
+	id   uint64
+	bind Bind
+}
+
+type cacheVerifier struct {
+	orm         *ormImplementation
+	schema      *entitySchema
+	repair      bool
+	report      *CacheReport
+	sets        []cacheVerifierSet
+	checkedSets map[string]bool
+}
+
+func VerifyCache[E any](orm ORM, where Where, repair bool) CacheReport {
+	return verifyCache(orm.(*ormImplementation), getEntitySchema[E](orm), where, repair)
+}
+
+func verifyCache(orm *ormImplementation, schema *entitySchema, where Where, repair bool) CacheReport {
+	report := CacheReport{}
+	v := &cacheVerifier{orm: orm, schema: schema, repair: repair, report: &report, checkedSets: make(map[string]bool)}
+	for columnName := range schema.cachedReferences {
+		v.sets = append(v.sets, cacheVerifierSet{name: columnName, columns: []string{columnName}})
+	}
+	if schema.cacheAll {
+		v.sets = append(v.sets, cacheVerifierSet{name: cacheAllFakeReferenceKey})
+	}
+	for indexName, definition := range schema.cachedIndexes {
+		v.sets = append(v.sets, cacheVerifierSet{name: indexName, columns: definition.Columns})
+	}
+	fullScan := where == nil
+	if fullScan {
+		where = allEntitiesWhere
+	}
+	where = schema.excludeDeleted(where)
+	lastID := uint64(0)
+	for {
+		parameters := append([]any{lastID}, where.GetParameters()...)
+		/* #nosec */
+		query := "SELECT " + schema.fieldsQuery + " FROM `" + schema.GetTableName() + "` WHERE `ID` > ? AND (" +
+			where.String() + ") ORDER BY `ID` LIMIT " + strconv.Itoa(verifyCacheChunkSize)
+		rows := v.load(query, parameters)
+		if len(rows) == 0 {
+			break
+		}
+		report.Checked += uint64(len(rows))
+		v.verifyRedisEntities(rows)
+		v.verifyLocalEntities(rows)
+		v.verifyUniqueIndexes(rows)
+		v.verifySets(rows)
+		lastID = rows[len(rows)-1].id
+		if len(rows) < verifyCacheChunkSize {
+			break
+		}
+	}
+	if fullScan {
+		v.verifyUniqueIndexOrphans()
+		v.verifyRedisEntityOrphans()
+	}
+	return report
+}
+
+func (v *cacheVerifier) load(query string, parameters []any) []cacheVerifierRow {
+	results, def := v.schema.GetDB().Query(v.orm, query, parameters...)
+	defer def()
+	var rows []cacheVerifierRow
+	for results.Next() {
+		pointers := prepareScan(v.schema)
+		results.Scan(pointers...)
+		value := reflect.New(v.schema.t)
+		deserializeFromDB(v.schema.fields, value.Elem(), pointers)
+		bind := make(Bind)
+		err := fillBindFromOneSource(v.orm, bind, value.Elem(), v.schema.fields, "")
+		checkError(err)
+		rows = append(rows, cacheVerifierRow{id: *pointers[0].(*uint64), bind: bind})
+	}
+	return rows
+}
+
+func (v *cacheVerifier) loadByIDs(ids []uint64) map[uint64]cacheVerifierRow {
+	rows := make(map[uint64]cacheVerifierRow, len(ids))
+	for start := 0; start < len(ids); start += verifyCacheChunkSize {
+		end := min(start+verifyCacheChunkSize, len(ids))
+		asStrings := make([]string, end-start)
+		for i, id := range ids[start:end] {
+			asStrings[i] = strconv.FormatUint(id, 10)
+		}
+		where := v.schema.excludeDeleted(NewWhere("`ID` IN (" + strings.Join(asStrings, ",") + ")"))
+		/* #nosec */
+		query := "SELECT " + v.schema.fieldsQuery + " FROM `" + v.schema.GetTableName() + "` WHERE " + where.String()
+		for _, row := range v.load(query, where.GetParameters()) {
+			rows[row.id] = row
+		}
+	}
+	return rows
+}
+
+func (v *cacheVerifier) add(entries *[]CacheReportEntry, cache, key, field string, id uint64) {
+	*entries = append(*entries, CacheReportEntry{Cache: cache, Key: key, Field: field, ID: id})
+	if v.repair {
+		v.report.Repaired++
+	}
+}
+
+func (v *cacheVerifier) verifyRedisEntities(rows []cacheVerifierRow) {
+	if !v.schema.hasRedisCache {
+		return
+	}
+	p := newRedisPipeLine(v.orm, v.schema.redisCache.GetCode())
+	l := int64(len(v.schema.columnNames) + 1)
+	lRanges := make([]*PipeLineSlice, len(rows))
+	for i, row := range rows {
+		lRanges[i] = p.LRange(v.schema.getCacheKey()+":"+strconv.FormatUint(row.id, 10), 0, l)
+	}
+	p.Exec(v.orm)
+	for i, row := range rows {
+		cacheKey := v.schema.getCacheKey() + ":" + strconv.FormatUint(row.id, 10)
+		cached := lRanges[i].Result()
+		if len(cached) == 0 {
+			// missing entity is loaded from MySQL on next read, nothing to repair
+			v.report.Missing = append(v.report.Missing, CacheReportEntry{Cache: CacheReportRedisEntity, Key: cacheKey, ID: row.id})
+		} else if len(cached) == 1 || !v.redisEntityEqual(cached, row.bind) {
+			v.add(&v.report.Stale, CacheReportRedisEntity, cacheKey, "", row.id)
+			if v.repair {
+				p.Del(cacheKey)
+			}
+		}
+	}
+	p.Exec(v.orm)
+}
+
+func (v *cacheVerifier) redisEntityEqual(cached []string, bind Bind) bool {
+	value := reflect.New(v.schema.t)
+	if !deserializeFromRedis(cached, v.schema, value.Elem()) {
+		return false
+	}
+	cachedBind := make(Bind)
+	err := fillBindFromOneSource(v.orm, cachedBind, value.Elem(), v.schema.fields, "")
+	checkError(err)
+	return cacheVerifierBindEqual(bind, cachedBind)
+}
+
+func (v *cacheVerifier) verifyLocalEntities(rows []cacheVerifierRow) {
+	if !v.schema.hasLocalCache {
+		return
+	}
+	lc := v.schema.localCache
+	for _, row := range rows {
+		cached, has := lc.getEntity(v.orm, row.id)
+		if !has {
+			continue
+		}
+		if cached != nil {
+			cachedBind := make(Bind)
+			lc.mutex.Lock()
+			err := fillBindFromOneSource(v.orm, cachedBind, reflect.ValueOf(cached).Elem(), v.schema.fields, "")
+			lc.mutex.Unlock()
+			checkError(err)
+			if cacheVerifierBindEqual(row.bind, cachedBind) {
+				continue
+			}
+		}
+		v.add(&v.report.Stale, CacheReportLocalEntity, v.schema.getCacheKey(), "", row.id)
+		if v.repair {
+			lc.removeEntity(v.orm, row.id)
+		}
+	}
+}
+
+func (v *cacheVerifier) verifyUniqueIndexes(rows []cacheVerifierRow) {
+	if len(v.schema.cachedUniqueIndexes) == 0 {
+		return
+	}
+	p := newRedisPipeLine(v.orm, v.schema.getForcedRedisCode())
+	type uniqueCheck struct {
+		key   string
+		field string
+		id    uint64
+		get   *PipeLineGet
+	}
+	var checks []uniqueCheck
+	for _, row := range rows {
+		for indexName, definition := range v.schema.cachedUniqueIndexes {
+			hField, hasKey := buildUniqueKeyHSetField(v.schema, definition.Columns, row.bind, nil)
+			if hasKey {
+				hSetKey := v.schema.getCacheKey() + ":" + indexName
+				checks = append(checks, uniqueCheck{key: hSetKey, field: hField, id: row.id, get: p.HGet(hSetKey, hField)})
+			}
+		}
+	}
+	p.Exec(v.orm)
+	for _, check := range checks {
+		cached, has := check.get.Result()
+		if !has {
+			// missing field is loaded from MySQL on next read, nothing to repair
+			v.report.Missing = append(v.report.Missing, CacheReportEntry{Cache: CacheReportUniqueIndex, Key: check.key, Field: check.field, ID: check.id})
+		} else if cached != strconv.FormatUint(check.id, 10) {
+			v.add(&v.report.Stale, CacheReportUniqueIndex, check.key, check.field, check.id)
+			if v.repair {
+				p.HDel(check.key, check.field)
+			}
+		}
+	}
+	p.Exec(v.orm)
+}
+
+func (s cacheVerifierSet) key(schema *entitySchema, bind Bind) (string, bool) {
+	if s.name == cacheAllFakeReferenceKey {
+		return schema.cacheKey + ":" + cacheAllFakeReferenceKey, true
+	}
+	if len(s.columns) == 1 && s.name == s.columns[0] {
+		if _, isReference := schema.cachedReferences[s.name]; isReference {
+			refID, _ := bind[s.name].(uint64)
+			if refID == 0 {
+				return "", false
+			}
+			return schema.cacheKey + ":" + s.name + ":" + strconv.FormatUint(refID, 10), true
+		}
+	}
+	attributes := make([]any, len(s.columns))
+	for i, column := range s.columns {
+		attributes[i] = bind[column]
+	}
+	return schema.cacheKey + ":" + s.name + ":" + strconv.FormatUint(hashIndexAttributes(attributes), 10), true
+}
+
+func (v *cacheVerifier) verifySets(rows []cacheVerifierRow) {
+	if len(v.sets) == 0 {
+		return
+	}
+	p := newRedisPipeLine(v.orm, v.schema.getForcedRedisCode())
+	expected := make(map[string][]uint64)
+	setOfKey := make(map[string]cacheVerifierSet)
+	members := make(map[string]*PipeLineSlice)
+	for _, row := range rows {
+		for _, set := range v.sets {
+			key, hasKey := set.key(v.schema, row.bind)
+			if !hasKey {
+				continue
+			}
+			if _, has := members[key]; !has {
+				members[key] = p.SMembers(key)
+				setOfKey[key] = set
+			}
+			expected[key] = append(expected[key], row.id)
+		}
+	}
+	p.Exec(v.orm)
+	unknown := make(map[string][]uint64)
+	var unknownIDs []uint64
+	for key, ids := range expected {
+		cached := make(map[string]bool)
+		for _, member := range members[key].Result() {
+			cached[member] = true
+		}
+		if !cached[redisValidSetValue] {
+			continue
+		}
+		for _, id := range ids {
+			idAsString := strconv.FormatUint(id, 10)
+			if cached[idAsString] {
+				delete(cached, idAsString)
+				continue
+			}
+			v.add(&v.report.Missing, CacheReportSet, key, "", id)
+			if v.repair {
+				// removed set is rebuilt from MySQL on next read
+				p.Del(key)
+			}
+		}
+		if v.checkedSets[key] {
+			continue
+		}
+		v.checkedSets[key] = true
+		for member := range cached {
+			if member == redisValidSetValue || member == cacheNilValue {
+				continue
+			}
+			id, err := strconv.ParseUint(member, 10, 64)
+			if err != nil {
+				continue
+			}
+			unknown[key] = append(unknown[key], id)
+			unknownIDs = append(unknownIDs, id)
+		}
+	}
+	if len(unknownIDs) > 0 {
+		existing := v.loadByIDs(unknownIDs)
+		for key, ids := range unknown {
+			for _, id := range ids {
+				row, has := existing[id]
+				if has {
+					rowKey, hasKey := setOfKey[key].key(v.schema, row.bind)
+					if hasKey && rowKey == key {
+						continue
+					}
+				}
+				v.add(&v.report.Orphaned, CacheReportSet, key, "", id)
+				if v.repair {
+					p.Del(key)
+				}
+			}
+		}
+	}
+	p.Exec(v.orm)
+}
+
+func (v *cacheVerifier) verifyUniqueIndexOrphans() {
+	r := v.orm.Engine().Redis(v.schema.getForcedRedisCode())
+	for indexName, definition := range v.schema.cachedUniqueIndexes {
+		hSetKey := v.schema.getCacheKey() + ":" + indexName
+		cursor := uint64(0)
+		for {
+			fields, next := r.HScan(v.orm, hSetKey, cursor, "*", verifyCacheChunkSize)
+			if len(fields) > 0 {
+				v.verifyUniqueIndexOrphansChunk(r, hSetKey, definition, fields)
+			}
+			cursor = next
+			if cursor == 0 {
+				break
+			}
+		}
+	}
+}
+
+func (v *cacheVerifier) verifyUniqueIndexOrphansChunk(r RedisCache, hSetKey string, definition indexDefinition, fields map[string]string) {
+	ids := make([]uint64, 0, len(fields))
+	for _, value := range fields {
+		id, _ := strconv.ParseUint(value, 10, 64)
+		ids = append(ids, id)
+	}
+	existing := v.loadByIDs(ids)
+	p := newRedisPipeLine(v.orm, r.GetCode())
+	for field, value := range fields {
+		id, _ := strconv.ParseUint(value, 10, 64)
+		row, has := existing[id]
+		if has {
+			hField, hasKey := buildUniqueKeyHSetField(v.schema, definition.Columns, row.bind, nil)
+			if hasKey && hField == field {
+				continue
+			}
+		}
+		v.add(&v.report.Orphaned, CacheReportUniqueIndex, hSetKey, field, id)
+		if v.repair {
+			p.HDel(hSetKey, field)
+		}
+	}
+	p.Exec(v.orm)
+}
+
+func (v *cacheVerifier) verifyRedisEntityOrphans() {
+	if !v.schema.hasRedisCache {
+		return
+	}
+	prefix := v.schema.getCacheKey() + ":"
+	cursor := uint64(0)
+	for {
+		keys, next := v.schema.redisCache.Scan(v.orm, cursor, prefix+"*", verifyCacheChunkSize)
+		var ids []uint64
+		for _, key := range keys {
+			id, err := strconv.ParseUint(key[len(prefix):], 10, 64)
+			if err == nil {
+				ids = append(ids, id)
+			}
+		}
+		if len(ids) > 0 {
+			v.verifyRedisEntityOrphansChunk(prefix, ids)
+		}
+		cursor = next
+		if cursor == 0 {
+			return
+		}
+	}
+}
+
+func (v *cacheVerifier) verifyRedisEntityOrphansChunk(prefix string, ids []uint64) {
+	existing := v.loadByIDs(ids)
+	p := newRedisPipeLine(v.orm, v.schema.redisCache.GetCode())
+	var orphans []uint64
+	var lRanges []*PipeLineSlice
+	for _, id := range ids {
+		if _, has := existing[id]; !has {
+			orphans = append(orphans, id)
+			lRanges = append(lRanges, p.LRange(prefix+strconv.FormatUint(id, 10), 0, 1))
+		}
+	}
+	if len(orphans) == 0 {
+		return
+	}
+	p.Exec(v.orm)
+	for i, id := range orphans {
+		if len(lRanges[i].Result()) < 2 {
+			continue
+		}
+		cacheKey := prefix + strconv.FormatUint(id, 10)
+		v.add(&v.report.Orphaned, CacheReportRedisEntity, cacheKey, "", id)
+		if v.repair {
+			p.Del(cacheKey)
+		}
+	}
+	p.Exec(v.orm)
+}
+
+func cacheVerifierBindEqual(expected, cached Bind) bool {
+	for column, value := range expected {
+		if fmt.Sprintf("%v", value) != fmt.Sprintf("%v", cached[column]) {
+			return false
+		}
+	}
+	return true
+}
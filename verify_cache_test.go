@@ -0,0 +1,85 @@
+package beeorm
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type verifyCacheEntity struct {
+	ID   uint64 `orm:"localCache;redisCache;cacheAll"`
+	Name string `orm:"unique=Name;cached"`
+	Age  uint8  `orm:"index=Age;cached"`
+}
+
+func TestVerifyCache(t *testing.T) {
+	var entity *verifyCacheEntity
+	orm := PrepareTables(t, NewRegistry(), entity)
+	schema := getEntitySchema[verifyCacheEntity](orm)
+	r := orm.Engine().Redis(DefaultPoolCode)
+	for i := 0; i < 10; i++ {
+		entity = NewEntity[verifyCacheEntity](orm)
+		entity.Name = "name " + strconv.Itoa(i)
+		entity.Age = uint8(i % 2)
+	}
+	assert.NoError(t, orm.Flush())
+	assert.NoError(t, WarmupCache[verifyCacheEntity](orm, nil))
+
+	report := VerifyCache[verifyCacheEntity](orm, nil, false)
+	assert.Equal(t, uint64(10), report.Checked)
+	assert.Len(t, report.Missing, 0)
+	assert.Len(t, report.Stale, 0)
+	assert.Len(t, report.Orphaned, 0)
+
+	id := strconv.FormatUint(entity.ID, 10)
+	r.Del(orm, schema.getCacheKey()+":"+id)
+	r.HSet(orm, schema.getCacheKey()+":Name", "invalid", id)
+	p := orm.RedisPipeLine(DefaultPoolCode)
+	p.SRem(schema.cacheKey+":"+cacheAllFakeReferenceKey, id)
+	p.Exec(orm)
+	r.SAdd(orm, schema.cacheKey+":"+cacheAllFakeReferenceKey, "1000")
+	r.RPush(orm, schema.getCacheKey()+":1000", schema.structureHash, "1000")
+	orm.Engine().DB(DefaultPoolCode).Exec(orm, "UPDATE `verifyCacheEntity` SET `Age` = 5 WHERE `ID` = ?", entity.ID)
+
+	report = VerifyCache[verifyCacheEntity](orm, NewWhere("`ID` = ?", entity.ID), false)
+	assert.Equal(t, uint64(1), report.Checked)
+	assert.Len(t, report.Orphaned, 0)
+
+	report = VerifyCache[verifyCacheEntity](orm, nil, true)
+	assert.Equal(t, uint64(10), report.Checked)
+	assert.Contains(t, report.Missing, CacheReportEntry{Cache: CacheReportRedisEntity, Key: schema.getCacheKey() + ":" + id, ID: entity.ID})
+	assert.Contains(t, report.Missing, CacheReportEntry{Cache: CacheReportSet, Key: schema.cacheKey + ":" + cacheAllFakeReferenceKey, ID: entity.ID})
+	assert.Contains(t, report.Stale, CacheReportEntry{Cache: CacheReportLocalEntity, Key: schema.getCacheKey(), ID: entity.ID})
+	assert.Contains(t, report.Orphaned, CacheReportEntry{Cache: CacheReportUniqueIndex, Key: schema.getCacheKey() + ":Name", Field: "invalid", ID: entity.ID})
+	assert.Contains(t, report.Orphaned, CacheReportEntry{Cache: CacheReportSet, Key: schema.cacheKey + ":" + cacheAllFakeReferenceKey, ID: 1000})
+	assert.Contains(t, report.Orphaned, CacheReportEntry{Cache: CacheReportRedisEntity, Key: schema.getCacheKey() + ":1000", ID: 1000})
+	assert.Equal(t, uint64(len(report.Missing)+len(report.Stale)+len(report.Orphaned)-1), report.Repaired)
+	assert.Equal(t, int64(0), r.Exists(orm, schema.getCacheKey()+":1000"))
+	assert.Equal(t, int64(0), r.Exists(orm, schema.cacheKey+":"+cacheAllFakeReferenceKey))
+	_, has := r.HGet(orm, schema.getCacheKey()+":Name", "invalid")
+	assert.False(t, has)
+
+	report = VerifyCache[verifyCacheEntity](orm, nil, false)
+	assert.Equal(t, []CacheReportEntry{{Cache: CacheReportRedisEntity, Key: schema.getCacheKey() + ":" + id, ID: entity.ID}}, report.Missing)
+	assert.Len(t, report.Stale, 0)
+	assert.Len(t, report.Orphaned, 0)
+	assert.Equal(t, uint64(0), report.Repaired)
+
+	_, found := GetByID[verifyCacheEntity](orm, entity.ID)
+	assert.True(t, found)
+	assert.Equal(t, 10, GetAll[verifyCacheEntity](orm).Len())
+	report = VerifyCache[verifyCacheEntity](orm, nil, false)
+	assert.Len(t, report.Missing, 0)
+	assert.Len(t, report.Stale, 0)
+	assert.Len(t, report.Orphaned, 0)
+
+	schema.localCache.Clear(orm)
+	loggerDB := &MockLogHandler{}
+	orm.RegisterQueryLogger(loggerDB, true, false, false)
+	e, found := GetByID[verifyCacheEntity](orm, entity.ID)
+	assert.True(t, found)
+	assert.Equal(t, uint8(5), e.Age)
+	assert.Equal(t, 10, GetAll[verifyCacheEntity](orm).Len())
+	assert.Len(t, loggerDB.Logs, 0)
+}
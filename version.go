@@ -0,0 +1,66 @@
+package beeorm
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+type VersionConflictError struct {
+	Schema  EntitySchema
+	ID      uint64
+	Version uint64
+}
+
+func (e *VersionConflictError) Error() string {
+	return fmt.Sprintf("version conflict in %s with ID %d, version %d is outdated", e.Schema.GetType().String(), e.ID, e.Version)
+}
+
+func (e *entitySchema) initVersionColumn() error {
+	for columnName, tags := range e.tags {
+		if tags["version"] != "true" {
+			continue
+		}
+		if e.versionColumn != "" {
+			return fmt.Errorf("only one version column is allowed in %s", e.t.String())
+		}
+		_, isColumn := e.columnMapping[columnName]
+		field, has := e.t.FieldByName(columnName)
+		if !isColumn || !has || columnName == "ID" {
+			return fmt.Errorf("invalid version column '%s' in %s", columnName, e.t.String())
+		}
+		switch field.Type.Kind() {
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		default:
+			return fmt.Errorf("version column '%s' in %s must be unsigned integer", columnName, e.t.String())
+		}
+		e.versionColumn = columnName
+	}
+	return nil
+}
+
+func (e *entitySchema) getVersion(elem reflect.Value) uint64 {
+	return elem.FieldByName(e.versionColumn).Uint()
+}
+
+func (e *asyncEvent) versionConflict(schema *entitySchema) *VersionConflictError {
+	if schema.versionColumn == "" {
+		return nil
+	}
+	version, has := e.Before[schema.versionColumn]
+	if !has {
+		return nil
+	}
+	id, _ := strconv.ParseUint(e.ID, 10, 64)
+	asUint64, _ := strconv.ParseUint(fmt.Sprintf("%v", version), 10, 64)
+	return &VersionConflictError{Schema: schema, ID: id, Version: asUint64}
+}
+
+func invalidateEntityCache(orm ORM, schema *entitySchema, id uint64) {
+	if schema.hasLocalCache {
+		schema.localCache.removeEntity(orm, id)
+	}
+	if schema.hasRedisCache {
+		schema.redisCache.Del(orm, schema.getCacheKey()+":"+strconv.FormatUint(id, 10))
+	}
+}
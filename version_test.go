@@ -0,0 +1,157 @@
+package beeorm
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type versionEntity struct {
+	ID      uint64 `orm:"localCache;redisCache"`
+	Name    string
+	Version uint32 `orm:"version"`
+}
+
+func TestVersionNoCache(t *testing.T) {
+	testVersion(t, false, false, false)
+}
+
+func TestVersionLocalCache(t *testing.T) {
+	testVersion(t, false, true, false)
+}
+
+func TestVersionRedis(t *testing.T) {
+	testVersion(t, false, false, true)
+}
+
+func TestVersionLocalCacheRedis(t *testing.T) {
+	testVersion(t, false, true, true)
+}
+
+func TestVersionNoCacheAsync(t *testing.T) {
+	testVersion(t, true, false, false)
+}
+
+func TestVersionLocalCacheAsync(t *testing.T) {
+	testVersion(t, true, true, false)
+}
+
+func TestVersionRedisAsync(t *testing.T) {
+	testVersion(t, true, false, true)
+}
+
+func TestVersionLocalCacheRedisAsync(t *testing.T) {
+	testVersion(t, true, true, true)
+}
+
+func testVersion(t *testing.T, async, local, redis bool) {
+	var entity *versionEntity
+	orm := PrepareTables(t, NewRegistry(), entity)
+	schema := GetEntitySchema[versionEntity](orm)
+	schema.DisableCache(!local, !redis)
+
+	entity = NewEntity[versionEntity](orm)
+	entity.Name = "a"
+	assert.NoError(t, orm.Flush())
+	id := entity.ID
+	assert.Equal(t, uint32(0), entity.Version)
+
+	orm2 := orm.Engine().NewORM(context.Background())
+	entity, _ = GetByID[versionEntity](orm, id)
+	entity2, _ := GetByID[versionEntity](orm2, id)
+	edit1 := EditEntity(orm, entity)
+	edit1.Name = "b"
+	edit2 := EditEntity(orm2, entity2)
+	edit2.Name = "c"
+
+	if async {
+		assert.NoError(t, orm.FlushAsync())
+		assert.NoError(t, orm2.FlushAsync())
+		assert.NoError(t, runAsyncConsumer(orm, false))
+		events := ReadAsyncFlushEvents(orm)
+		assert.Len(t, events, 1)
+		assert.Equal(t, uint64(1), events[0].ErrorsCount())
+		errs := events[0].Errors(1, false)
+		assert.Len(t, errs, 1)
+		assert.Equal(t, "version conflict in beeorm.versionEntity with ID 1, version 0 is outdated", errs[0].Error)
+		events[0].TrimErrors(1)
+	} else {
+		assert.NoError(t, orm.Flush())
+		err := orm2.Flush()
+		assert.EqualError(t, err, "version conflict in beeorm.versionEntity with ID 1, version 0 is outdated")
+		conflict, isConflict := err.(*VersionConflictError)
+		assert.True(t, isConflict)
+		assert.Equal(t, id, conflict.ID)
+		assert.Equal(t, uint64(0), conflict.Version)
+	}
+
+	orm3 := orm.Engine().NewORM(context.Background())
+	entity, _ = GetByID[versionEntity](orm3, id)
+	assert.Equal(t, "b", entity.Name)
+	assert.Equal(t, uint32(1), entity.Version)
+
+	entity = EditEntity(orm3, entity)
+	entity.Name = "d"
+	if async {
+		assert.NoError(t, orm3.FlushAsync())
+		assert.NoError(t, runAsyncConsumer(orm3, false))
+	} else {
+		assert.NoError(t, orm3.Flush())
+	}
+	entity, _ = GetByID[versionEntity](orm3, id)
+	assert.Equal(t, "d", entity.Name)
+	assert.Equal(t, uint32(2), entity.Version)
+
+	entity, _ = GetByID[versionEntity](orm3, id)
+	assert.NoError(t, EditEntityField(orm3, entity, "Name", "e"))
+	if async {
+		assert.NoError(t, orm3.FlushAsync())
+		assert.NoError(t, runAsyncConsumer(orm3, false))
+	} else {
+		assert.NoError(t, orm3.Flush())
+	}
+	entity, _ = GetByID[versionEntity](orm3, id)
+	assert.Equal(t, "e", entity.Name)
+	assert.Equal(t, uint32(3), entity.Version)
+
+	entity, _ = GetByID[versionEntity](orm3, id)
+	outdated := EditEntity(orm2, entity)
+	outdated.Version = 1
+	outdated.Name = "f"
+	if async {
+		assert.NoError(t, orm2.FlushAsync())
+		assert.NoError(t, runAsyncConsumer(orm2, false))
+		assert.Equal(t, uint64(1), ReadAsyncFlushEvents(orm2)[0].ErrorsCount())
+	} else {
+		assert.IsType(t, &VersionConflictError{}, orm2.Flush())
+	}
+	entity, _ = GetByID[versionEntity](orm3, id)
+	assert.Equal(t, "e", entity.Name)
+	assert.Equal(t, uint32(3), entity.Version)
+}
+
+func TestVersionFailedFlush(t *testing.T) {
+	var entity *versionEntity
+	var invalid *validationEntity
+	orm := PrepareTables(t, NewRegistry(), entity, invalid)
+
+	entity = NewEntity[versionEntity](orm)
+	entity.Name = "a"
+	assert.NoError(t, orm.Flush())
+
+	entity = EditEntity(orm, entity)
+	entity.Name = "b"
+	invalid = NewEntity[validationEntity](orm)
+	invalid.Name = "a"
+	invalid.Price = 1
+	assert.IsType(t, &ValidationError{}, orm.Flush())
+	assert.Equal(t, uint32(0), entity.Version)
+
+	invalid.Name = "tom"
+	assert.NoError(t, orm.Flush())
+	assert.Equal(t, uint32(1), entity.Version)
+	entity, _ = GetByID[versionEntity](orm, entity.ID)
+	assert.Equal(t, "b", entity.Name)
+	assert.Equal(t, uint32(1), entity.Version)
+}
@@ -0,0 +1,205 @@
+package beeorm
+
+import (
+	"context"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+const warmupCacheChunkSize = 1000
+const warmupCacheStagingSuffix = ":warmup"
+
+type WarmupCacheOptions struct {
+	Where         Where
+	ChunkSize     int
+	RowsPerSecond int
+	Progress      func(progress WarmupCacheProgress)
+}
+
+type WarmupCacheProgress struct {
+	Rows    uint64
+	LastID  uint64
+	Elapsed time.Duration
+}
+
+type warmupCacheSets struct {
+	schema *entitySchema
+	staged map[string]bool
+}
+
+func WarmupCache[E any](orm ORM, opts *WarmupCacheOptions) error {
+	return warmupCache(orm.(*ormImplementation), getEntitySchema[E](orm), opts)
+}
+
+func warmupCache(orm *ormImplementation, schema *entitySchema, opts *WarmupCacheOptions) (err error) {
+	if opts == nil {
+		opts = &WarmupCacheOptions{}
+	}
+	chunkSize := opts.ChunkSize
+	if chunkSize <= 0 {
+		chunkSize = warmupCacheChunkSize
+	}
+	where := opts.Where
+	var sets *warmupCacheSets
+	if where == nil {
+		where = allEntitiesWhere
+		sets = &warmupCacheSets{schema: schema, staged: make(map[string]bool)}
+		defer func() {
+			if rec := recover(); rec != nil {
+				sets.discard(orm)
+				panic(rec)
+			}
+			if err != nil {
+				sets.discard(orm)
+			}
+		}()
+		if schema.cacheAll {
+			cache := &bulkCacheActions{orm: orm, pipeLines: make(map[string]*RedisPipeLine)}
+			sets.stage(cache, schema.cacheKey+":"+cacheAllFakeReferenceKey)
+			cache.exec()
+		}
+	}
+	where = schema.excludeDeleted(where)
+	progress := WarmupCacheProgress{}
+	start := time.Now()
+	for {
+		if err = orm.Context().Err(); err != nil {
+			return err
+		}
+		parameters := append([]any{progress.LastID}, where.GetParameters()...)
+		/* #nosec */
+		query := "SELECT " + schema.fieldsQuery + " FROM `" + schema.GetTableName() + "` WHERE `ID` > ? AND (" +
+			where.String() + ") ORDER BY `ID` LIMIT " + strconv.Itoa(chunkSize)
+		rows := warmupCacheChunk(orm, schema, query, parameters, sets, &progress.LastID)
+		progress.Rows += uint64(rows)
+		progress.Elapsed = time.Since(start)
+		if opts.Progress != nil && rows > 0 {
+			opts.Progress(progress)
+		}
+		if rows < chunkSize {
+			break
+		}
+		if opts.RowsPerSecond > 0 {
+			wait := time.Duration(float64(progress.Rows)/float64(opts.RowsPerSecond)*float64(time.Second)) - time.Since(start)
+			if wait > 0 {
+				select {
+				case <-orm.Context().Done():
+					return orm.Context().Err()
+				case <-time.After(wait):
+				}
+			}
+		}
+	}
+	if sets != nil {
+		sets.publish(orm)
+	}
+	return nil
+}
+
+func warmupCacheChunk(orm *ormImplementation, schema *entitySchema, query string, parameters []any, sets *warmupCacheSets, lastID *uint64) int {
+	cache := &bulkCacheActions{orm: orm, pipeLines: make(map[string]*RedisPipeLine)}
+	results, def := schema.GetDB().Query(orm, query, parameters...)
+	defer def()
+	rows := 0
+	for results.Next() {
+		pointers := prepareScan(schema)
+		results.Scan(pointers...)
+		value := reflect.New(schema.t)
+		deserializeFromDB(schema.fields, value.Elem(), pointers)
+		id := *pointers[0].(*uint64)
+		idAsString := strconv.FormatUint(id, 10)
+		if schema.hasLocalCache {
+			if _, has := schema.localCache.getEntity(orm, id); !has {
+				schema.localCache.setEntity(orm, id, value.Interface())
+			}
+		}
+		bind := make(Bind)
+		err := fillBindFromOneSource(orm, bind, value.Elem(), schema.fields, "")
+		checkError(err)
+		if schema.hasRedisCache {
+			p := cache.pipeLine(schema.redisCache.GetCode())
+			cacheKey := schema.getCacheKey() + ":" + idAsString
+			// entities updated by flushes after the chunk was loaded are kept
+			p.Eval(redisCacheEntityFillScript, []string{cacheKey}, convertBindToRedisValue(bind, schema)...)
+			schema.setRedisCacheTTL(p, cacheKey)
+		}
+		for indexName, definition := range schema.cachedUniqueIndexes {
+			hField, hasKey := buildUniqueKeyHSetField(schema, definition.Columns, bind, nil)
+			if hasKey {
+				cache.pipeLine(schema.getForcedRedisCode()).HSet(schema.getCacheKey()+":"+indexName, hField, idAsString)
+			}
+		}
+		if sets != nil {
+			for columnName := range schema.cachedReferences {
+				refID, _ := bind[columnName].(uint64)
+				if refID > 0 {
+					sets.add(cache, schema.cacheKey+":"+columnName+":"+strconv.FormatUint(refID, 10), idAsString)
+				}
+			}
+			if schema.cacheAll {
+				sets.add(cache, schema.cacheKey+":"+cacheAllFakeReferenceKey, idAsString)
+			}
+			for indexName, definition := range schema.cachedIndexes {
+				attributes := make([]any, len(definition.Columns))
+				for i, column := range definition.Columns {
+					attributes[i] = bind[column]
+				}
+				sets.add(cache, schema.cacheKey+":"+indexName+":"+strconv.FormatUint(hashIndexAttributes(attributes), 10), idAsString)
+			}
+		}
+		*lastID = id
+		rows++
+	}
+	def()
+	cache.exec()
+	return rows
+}
+
+func (b *bulkCacheActions) exec() {
+	for _, pipeLine := range b.pipeLines {
+		if pipeLine.commands > 0 {
+			pipeLine.Exec(b.orm)
+		}
+	}
+}
+
+func (s *warmupCacheSets) stage(cache *bulkCacheActions, key string) {
+	p := cache.pipeLine(s.schema.getForcedRedisCode())
+	p.Del(key + warmupCacheStagingSuffix)
+	p.SAdd(key+warmupCacheStagingSuffix, redisValidSetValue)
+	s.staged[key] = true
+}
+
+func (s *warmupCacheSets) add(cache *bulkCacheActions, key, member string) {
+	if !s.staged[key] {
+		s.stage(cache, key)
+	}
+	cache.pipeLine(s.schema.getForcedRedisCode()).SAdd(key+warmupCacheStagingSuffix, member)
+}
+
+func (s *warmupCacheSets) publish(orm *ormImplementation) {
+	p := newRedisPipeLine(orm, s.schema.getForcedRedisCode())
+	for key := range s.staged {
+		// sets already maintained by flushes are kept, staged copy may miss their recent changes
+		p.RenameNX(key+warmupCacheStagingSuffix, key)
+		p.Del(key + warmupCacheStagingSuffix)
+		s.schema.setRedisCacheTTL(p, key)
+		if p.commands >= redisRPushPackSize {
+			p.Exec(orm)
+		}
+	}
+	p.Exec(orm)
+}
+
+func (s *warmupCacheSets) discard(orm *ormImplementation) {
+	orm = orm.CloneWithContext(context.Background()).(*ormImplementation)
+	p := newRedisPipeLine(orm, s.schema.getForcedRedisCode())
+	for key := range s.staged {
+		p.Del(key + warmupCacheStagingSuffix)
+		if p.commands >= redisRPushPackSize {
+			p.Exec(orm)
+		}
+	}
+	p.Exec(orm)
+}
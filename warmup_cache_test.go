@@ -0,0 +1,105 @@
+package beeorm
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type warmupCacheEntity struct {
+	ID   uint64 `orm:"localCache;redisCache;cacheAll"`
+	Name string `orm:"unique=Name;cached"`
+	Age  uint8  `orm:"index=Age;cached"`
+}
+
+func TestWarmupCache(t *testing.T) {
+	var entity *warmupCacheEntity
+	orm := PrepareTables(t, NewRegistry(), entity)
+	schema := getEntitySchema[warmupCacheEntity](orm)
+	r := orm.Engine().Redis(DefaultPoolCode)
+	for i := 0; i < 25; i++ {
+		entity = NewEntity[warmupCacheEntity](orm)
+		entity.Name = "name " + strconv.Itoa(i)
+		entity.Age = uint8(i % 5)
+	}
+	assert.NoError(t, orm.Flush())
+	r.FlushDB(orm)
+	schema.localCache.Clear(orm)
+
+	var progress []WarmupCacheProgress
+	err := WarmupCache[warmupCacheEntity](orm, &WarmupCacheOptions{ChunkSize: 10, Progress: func(p WarmupCacheProgress) {
+		progress = append(progress, p)
+	}})
+	assert.NoError(t, err)
+	assert.Len(t, progress, 3)
+	assert.Equal(t, uint64(25), progress[2].Rows)
+	assert.Equal(t, entity.ID, progress[2].LastID)
+	assert.Equal(t, uint64(25), schema.localCache.GetUsage()[0].Used)
+
+	loggerDB := &MockLogHandler{}
+	orm.RegisterQueryLogger(loggerDB, true, false, false)
+	schema.localCache.Clear(orm)
+	assert.Equal(t, 25, GetAll[warmupCacheEntity](orm).Len())
+	assert.Equal(t, 5, GetByIndex[warmupCacheEntity](orm, "Age", 3).Len())
+	_, found := GetByUniqueIndex[warmupCacheEntity](orm, "Name", "name 7")
+	assert.True(t, found)
+	e, found := GetByID[warmupCacheEntity](orm, entity.ID)
+	assert.True(t, found)
+	assert.Equal(t, "name 24", e.Name)
+	assert.Len(t, loggerDB.Logs, 0)
+	assert.Equal(t, int64(0), r.Exists(orm, schema.cacheKey+":"+cacheAllFakeReferenceKey+warmupCacheStagingSuffix))
+
+	r.FlushDB(orm)
+	schema.localCache.Clear(orm)
+	err = WarmupCache[warmupCacheEntity](orm, &WarmupCacheOptions{Where: NewWhere("Age = ?", 1), RowsPerSecond: 1000})
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(5), schema.localCache.GetUsage()[0].Used)
+	assert.Equal(t, int64(0), r.Exists(orm, schema.cacheKey+":"+cacheAllFakeReferenceKey))
+
+	r.FlushDB(orm)
+	ageKey := schema.cacheKey + ":Age:" + strconv.FormatUint(hashIndexAttributes([]any{uint8(2)}), 10)
+	r.SAdd(orm, ageKey, redisValidSetValue, "1000")
+	assert.NoError(t, WarmupCache[warmupCacheEntity](orm, nil))
+	assert.ElementsMatch(t, []string{redisValidSetValue, "1000"}, r.SMembers(orm, ageKey))
+	assert.Equal(t, int64(0), r.Exists(orm, ageKey+warmupCacheStagingSuffix))
+	assert.Equal(t, 5, GetByIndex[warmupCacheEntity](orm, "Age", 3).Len())
+
+	r.FlushDB(orm)
+	schema.localCache.Clear(orm)
+	entityKey := schema.getCacheKey() + ":" + strconv.FormatUint(entity.ID, 10)
+	r.RPush(orm, entityKey, "newer")
+	newer := &warmupCacheEntity{ID: entity.ID, Name: "newer"}
+	schema.localCache.setEntity(orm, entity.ID, newer)
+	assert.NoError(t, WarmupCache[warmupCacheEntity](orm, nil))
+	assert.Equal(t, []string{"newer"}, r.LRange(orm, entityKey, 0, -1))
+	e, found = GetByID[warmupCacheEntity](orm, entity.ID)
+	assert.True(t, found)
+	assert.Equal(t, "newer", e.Name)
+
+	db := orm.Engine().DB(DefaultPoolCode)
+	originDB := db.GetDBClient()
+	queries := 0
+	db.SetMockDBClient(&MockDBClient{OriginDB: originDB, QueryMock: func(query string, args ...any) (*sql.Rows, error) {
+		queries++
+		if queries > 1 {
+			return nil, errors.New("connection lost")
+		}
+		return originDB.Query(query, args...)
+	}})
+	r.FlushDB(orm)
+	assert.PanicsWithError(t, "connection lost", func() {
+		_ = WarmupCache[warmupCacheEntity](orm, &WarmupCacheOptions{ChunkSize: 10})
+	})
+	db.SetMockDBClient(originDB)
+	assert.Equal(t, int64(0), r.Exists(orm, schema.cacheKey+":"+cacheAllFakeReferenceKey+warmupCacheStagingSuffix))
+	assert.Equal(t, int64(0), r.Exists(orm, ageKey+warmupCacheStagingSuffix))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = WarmupCache[warmupCacheEntity](orm.CloneWithContext(ctx), nil)
+	assert.ErrorIs(t, err, context.Canceled)
+}
@@ -94,6 +94,11 @@ func validateOrmMysqlURI(registry *registry, value any, key string) error {
 			if err != nil {
 				return err
 			}
+		case "maxInsertRows":
+			options.MaxInsertRows, err = validateOrmInt(v, "maxInsertRows")
+			if err != nil {
+				return err
+			}
 		}
 	}
 	registry.RegisterMySQL(uri, key, options)